@@ -0,0 +1,191 @@
+package flow
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+// Store is a typed key into the Blackboard of a Workflow run, for Steps to
+// publish values to their siblings and downstreams without closures over
+// shared variables. Declare a package-level variable per value:
+//
+//	var Version = flow.Store[string]{Name: "version"}
+//
+//	// a Step publishes:
+//	Version.Put(ctx, "v1.2.3")
+//
+//	// a downstream Step reads:
+//	v, ok := Version.Get(ctx)
+//
+//	// a sibling waits until it's published:
+//	v, err := Version.Wait(ctx)
+//
+// Keys are equal when their Name and T are; Name also labels the value in
+// Blackboard.Snapshot, so keep it unique.
+type Store[T any] struct{ Name string }
+
+// Put publishes v under k in the Blackboard of ctx, waking up the Waits for
+// it. Without a Blackboard in ctx (outside a Workflow run), Put does nothing.
+func (k Store[T]) Put(ctx context.Context, v T) {
+	by, _ := publisherKey.From(ctx)
+	BlackboardFrom(ctx).put(k, v, by.Steper)
+}
+
+// Get returns the value published under k in the Blackboard of ctx, and
+// whether there is one.
+func (k Store[T]) Get(ctx context.Context) (T, bool) {
+	return k.Read(BlackboardFrom(ctx))
+}
+
+// Wait returns the value published under k in the Blackboard of ctx,
+// waiting for a Put if there is none yet, or the error of ctx if it is done
+// first.
+func (k Store[T]) Wait(ctx context.Context) (T, error) {
+	b := BlackboardFrom(ctx)
+	for {
+		v, ok, changed := b.get(k)
+		if ok {
+			return v.(T), nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			var zero T
+			return zero, context.Cause(ctx)
+		}
+	}
+}
+
+// Read returns the value published under k in b, and whether there is one,
+// e.g. from Workflow.Blackboard after a run.
+func (k Store[T]) Read(b *Blackboard) (T, bool) {
+	v, ok, _ := b.get(k)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return v.(T), true
+}
+
+// Blackboard is the concurrency-safe key-value store shared by the Steps of
+// a Workflow run, read and written through Store keys. Sub-workflows use the
+// Blackboard of the Workflow running them.
+//
+// Each Do starts with an empty Blackboard, unless some Steps keep their
+// result from a previous run (see RetryFailed and From): the values they
+// published are kept, while those of the Steps running again are dropped,
+// so a Wait doesn't see a stale value. Workflow.Reset does the same.
+//
+// A nil *Blackboard is empty, and Put on it does nothing.
+type Blackboard struct {
+	mu      sync.Mutex
+	values  map[storeKey]any
+	by      map[storeKey]Steper // the root Step which published each value; see publisher.
+	changed chan struct{}       // closed, and replaced, by each put.
+}
+
+// storeKey is implemented by every Store[T].
+type storeKey interface{ name() string }
+
+func (k Store[T]) name() string { return k.Name }
+
+// blackboardKey carries the Blackboard of the running Workflow.
+var blackboardKey = ContextKey[*Blackboard]{}
+
+// publisher is the root Step, of the Workflow the Blackboard belongs to,
+// whose run a context belongs to: Steps of sub-workflows publish on behalf
+// of the root Step containing them.
+type publisher struct{ Steper }
+
+var publisherKey = ContextKey[publisher]{}
+
+// BlackboardFrom returns the Blackboard of the Workflow run ctx belongs to,
+// nil if none.
+func BlackboardFrom(ctx context.Context) *Blackboard {
+	b, _ := blackboardKey.From(ctx)
+	return b
+}
+
+func (b *Blackboard) put(k storeKey, v any, by Steper) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.values == nil {
+		b.values = make(map[storeKey]any)
+		b.by = make(map[storeKey]Steper)
+	}
+	b.values[k] = v
+	b.by[k] = by
+	if b.changed != nil {
+		close(b.changed)
+		b.changed = nil
+	}
+}
+
+// get returns the value under k, whether there is one, and a channel closed
+// by the next put.
+func (b *Blackboard) get(k storeKey) (any, bool, <-chan struct{}) {
+	if b == nil {
+		return nil, false, nil // nil channel: blocks forever
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if v, ok := b.values[k]; ok {
+		return v, true, nil
+	}
+	if b.changed == nil {
+		b.changed = make(chan struct{})
+	}
+	return nil, false, b.changed
+}
+
+// Snapshot returns a copy of the published values, by key Name, e.g. to log
+// or inspect them while debugging.
+func (b *Blackboard) Snapshot() map[string]any {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	rv := make(map[string]any, len(b.values))
+	for k, v := range b.values {
+		rv[k.name()] = v
+	}
+	return rv
+}
+
+// Reset removes every published value.
+func (b *Blackboard) Reset() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	clear(b.values)
+	clear(b.by)
+}
+
+// retain removes the values not published by a Step in keep.
+func (b *Blackboard) retain(keep map[Steper]StepResult) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for k, by := range b.by {
+		if _, ok := keep[by]; !ok || by == nil {
+			delete(b.values, k)
+			delete(b.by, k)
+		}
+	}
+}
+
+// clone returns a copy of b.
+func (b *Blackboard) clone() *Blackboard {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return &Blackboard{values: maps.Clone(b.values), by: maps.Clone(b.by)}
+}
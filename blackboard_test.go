@@ -0,0 +1,157 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlackboard(t *testing.T) {
+	t.Parallel()
+	version := Store[string]{Name: "version"}
+	count := Store[int]{Name: "count"}
+	publish := func(v string) *Function[struct{}, struct{}] {
+		return Func("publish", func(ctx context.Context) error {
+			version.Put(ctx, v)
+			return nil
+		})
+	}
+	t.Run("downstream reads what upstream published", func(t *testing.T) {
+		var got string
+		pub := publish("v1")
+		read := Func("read", func(ctx context.Context) error {
+			var ok bool
+			got, ok = version.Get(ctx)
+			if !ok {
+				return errors.New("no version")
+			}
+			return nil
+		})
+		w := new(Workflow).Add(Step(read).DependsOn(pub))
+		assert.NoError(t, w.Do(context.Background()))
+		assert.Equal(t, "v1", got)
+		v, ok := version.Read(w.Blackboard())
+		assert.True(t, ok)
+		assert.Equal(t, "v1", v)
+		_, ok = count.Read(w.Blackboard())
+		assert.False(t, ok)
+		assert.Equal(t, map[string]any{"version": "v1"}, w.Blackboard().Snapshot())
+	})
+	t.Run("sibling waits", func(t *testing.T) {
+		var got string
+		wait := Func("wait", func(ctx context.Context) error {
+			var err error
+			got, err = version.Wait(ctx)
+			return err
+		})
+		pub := Func("publish", func(ctx context.Context) error {
+			time.Sleep(10 * time.Millisecond)
+			count.Put(ctx, 1) // doesn't wake up the wait for good
+			version.Put(ctx, "v2")
+			return nil
+		})
+		w := new(Workflow).Add(Steps(wait, pub))
+		assert.NoError(t, w.Do(context.Background()))
+		assert.Equal(t, "v2", got)
+	})
+	t.Run("Wait stops with the context", func(t *testing.T) {
+		wait := Func("wait", func(ctx context.Context) error {
+			_, err := version.Wait(ctx)
+			return err
+		})
+		w := new(Workflow).Add(Step(wait).Timeout(10 * time.Millisecond))
+		assert.ErrorIs(t, w.Do(context.Background()), context.DeadlineExceeded)
+	})
+	t.Run("sub-workflows share the parent's", func(t *testing.T) {
+		var got string
+		inner := new(Workflow).Add(Step(Func("read", func(ctx context.Context) error {
+			got, _ = version.Get(ctx)
+			return nil
+		})))
+		pub := publish("v3")
+		w := new(Workflow).Add(Step(inner).DependsOn(pub))
+		assert.NoError(t, w.Do(context.Background()))
+		assert.Equal(t, "v3", got)
+		assert.Empty(t, inner.Blackboard().Snapshot())
+	})
+	t.Run("each run starts empty, Reset empties", func(t *testing.T) {
+		seen := map[string]bool{}
+		read := Func("read", func(ctx context.Context) error {
+			_, seen["before"] = count.Get(ctx)
+			count.Put(ctx, 1)
+			return nil
+		})
+		w := new(Workflow).Add(Step(read))
+		assert.NoError(t, w.Do(context.Background()))
+		assert.NoError(t, w.Do(context.Background()))
+		assert.False(t, seen["before"])
+		assert.NoError(t, w.Reset())
+		assert.Empty(t, w.Blackboard().Snapshot())
+	})
+	t.Run("RetryFailed keeps the values of kept steps", func(t *testing.T) {
+		fail := true
+		var got string
+		pub := publish("v4")
+		flaky := Func("flaky", func(ctx context.Context) error {
+			if fail {
+				return errors.New("flaky")
+			}
+			got, _ = version.Get(ctx)
+			return nil
+		})
+		w := new(Workflow).Add(Step(flaky).DependsOn(pub))
+		assert.Error(t, w.Do(context.Background()))
+		fail = false
+		assert.NoError(t, w.RetryFailed(context.Background()))
+		assert.Equal(t, "v4", got)
+
+		got = ""
+		sub := w.From(flaky)
+		assert.NoError(t, sub.Reset())
+		assert.NoError(t, sub.Do(context.Background()))
+		assert.Equal(t, "v4", got, "From copies the values of the preset steps")
+		sub.Blackboard().Reset()
+		assert.Contains(t, w.Blackboard().Snapshot(), "version", "w's Blackboard is its own")
+	})
+	t.Run("RetryFailed drops the values of retried steps", func(t *testing.T) {
+		first, stale := true, true
+		seen := make(chan struct{})
+		observe := Func("observe", func(ctx context.Context) error {
+			if first {
+				return errors.New("first")
+			}
+			_, stale = version.Get(ctx)
+			close(seen)
+			return nil
+		})
+		flaky := Func("flaky", func(ctx context.Context) error {
+			if first {
+				version.Put(ctx, "stale")
+				return errors.New("flaky")
+			}
+			<-seen
+			version.Put(ctx, "fresh")
+			return nil
+		})
+		w := new(Workflow).Add(Steps(observe, flaky))
+		assert.Error(t, w.Do(context.Background()))
+		assert.Equal(t, map[string]any{"version": "stale"}, w.Blackboard().Snapshot())
+		first = false
+		assert.NoError(t, w.RetryFailed(context.Background()))
+		assert.False(t, stale)
+		assert.Equal(t, map[string]any{"version": "fresh"}, w.Blackboard().Snapshot())
+	})
+	t.Run("outside a Workflow", func(t *testing.T) {
+		ctx := context.Background()
+		version.Put(ctx, "ignored")
+		_, ok := version.Get(ctx)
+		assert.False(t, ok)
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := version.Wait(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
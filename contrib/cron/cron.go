@@ -0,0 +1,37 @@
+// Package flowcron parses cron expressions into flowschedule Schedules:
+//
+//	import (
+//	    flowcron "github.com/Azure/go-workflow/contrib/cron"
+//	    "github.com/Azure/go-workflow/flowschedule"
+//	)
+//
+//	s := &flowschedule.Scheduler{
+//	    Schedule: flowcron.MustParse("*/5 * * * *"),
+//	    Workflow: flowschedule.Reuse(w),
+//	}
+//
+// It's a separate module so github.com/robfig/cron/v3 doesn't enter the
+// core module's transitive graph.
+package flowcron
+
+import (
+	"github.com/Azure/go-workflow/flowschedule"
+	"github.com/robfig/cron/v3"
+)
+
+// Parse parses a standard five-field cron expression ("*/5 * * * *"), or a
+// descriptor such as "@hourly" or "@every 90s", optionally prefixed with a
+// time zone ("CRON_TZ=Europe/Paris 0 6 * * *"). Without a time zone, due
+// times are in the location of the Scheduler's Clock.
+func Parse(expr string) (flowschedule.Schedule, error) {
+	return cron.ParseStandard(expr)
+}
+
+// MustParse is Parse, panicking if expr doesn't parse.
+func MustParse(expr string) flowschedule.Schedule {
+	s, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
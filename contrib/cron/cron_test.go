@@ -0,0 +1,22 @@
+package flowcron_test
+
+import (
+	"testing"
+	"time"
+
+	flowcron "github.com/Azure/go-workflow/contrib/cron"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	at := time.Date(2026, 10, 18, 12, 3, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 10, 18, 12, 5, 0, 0, time.UTC), flowcron.MustParse("*/5 * * * *").Next(at))
+	assert.Equal(t, time.Date(2026, 10, 18, 13, 0, 0, 0, time.UTC), flowcron.MustParse("@hourly").Next(at))
+	assert.Equal(t, at.Add(90*time.Second), flowcron.MustParse("@every 90s").Next(at))
+	assert.Equal(t, time.Date(2026, 10, 19, 4, 0, 0, 0, time.UTC), flowcron.MustParse("CRON_TZ=Europe/Paris 0 6 * * *").Next(at))
+
+	_, err := flowcron.Parse("not a cron")
+	assert.Error(t, err)
+	assert.Panics(t, func() { flowcron.MustParse("not a cron") })
+}
@@ -0,0 +1,19 @@
+module github.com/Azure/go-workflow/contrib/cron
+
+go 1.23.0
+
+replace github.com/Azure/go-workflow => ../..
+
+require (
+	github.com/Azure/go-workflow v0.0.0-00010101000000-000000000000
+	github.com/robfig/cron/v3 v3.0.1
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	github.com/benbjohnson/clock v1.3.5 // indirect
+	github.com/cenkalti/backoff/v4 v4.3.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
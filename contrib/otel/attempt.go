@@ -23,11 +23,13 @@ import (
 // WithAttemptAttributes cannot override them.
 //
 // On a non-nil error from next() the span records the error via
-// span.RecordError and sets its status to codes.Error. context.Canceled
-// is treated like any other error (no special-case).
+// span.RecordError, and sets its status to codes.Error unless the error
+// cancels or skips the Step (ErrSkip, ErrCancel, context.Canceled, ...) or
+// wraps ErrSucceed.
 //
 // Steps that the scheduler settles inline (Skipped or Canceled by their
-// Condition) bypass the interceptor chain entirely and produce no span.
+// Condition) bypass the interceptor chain entirely and produce no attempt
+// span.
 func NewAttemptInterceptor(opts ...Option) flow.AttemptInterceptor {
 	cfg := newConfig(opts)
 	tracer := cfg.resolveTracer()
@@ -52,7 +54,9 @@ func NewAttemptInterceptor(opts ...Option) flow.AttemptInterceptor {
 		err := next(ctx)
 		if err != nil {
 			span.RecordError(err)
-			span.SetStatus(codes.Error, err.Error())
+			if flow.StatusOf(err) == flow.Failed {
+				span.SetStatus(codes.Error, err.Error())
+			}
 		}
 		return err
 	})
@@ -170,7 +170,7 @@ func TestAttemptInterceptor_ContextCanceled(t *testing.T) {
 	spans := rec.Ended()
 	require.NotEmpty(t, spans)
 	s := spans[0]
-	assert.Equal(t, codes.Error, s.Status().Code)
+	assert.Equal(t, codes.Unset, s.Status().Code, "a canceled attempt is not an error")
 	require.NotEmpty(t, s.Events())
 	assert.Equal(t, exceptionEventName, s.Events()[0].Name)
 }
@@ -4,8 +4,18 @@ package flowotel
 const (
 	attrStepName    = "workflow.step.name"
 	attrStepStatus  = "workflow.step.status"
+	attrStepReason  = "workflow.step.reason"
 	attrStepAttempt = "workflow.step.attempt"
 
+	attrWorkflowName   = "workflow.name"
+	attrWorkflowPath   = "workflow.path"
+	attrWorkflowStatus = "workflow.status"
+	attrWorkflowSteps  = "workflow.steps" // prefix of the per-status step counts, e.g. workflow.steps.failed
+
 	statusSuccess = "success"
 	statusError   = "error"
+
+	// reasonCondition is the workflow.step.reason of a Step settled by its
+	// Condition without an error.
+	reasonCondition = "condition"
 )
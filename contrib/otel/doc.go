@@ -25,10 +25,26 @@
 //
 // See the runnable Example for a complete wiring with a stdout exporter.
 //
+// # Workflow spans
+//
+// NewWorkflowHook returns a flow.WorkflowHook that wraps each Do in a
+// workflow span, the parent of the step spans:
+//
+//	w.Option.Hooks = append(w.Option.Hooks, flowotel.NewWorkflowHook(flowotel.WithTracerProvider(tp)))
+//
+// It carries workflow.name, workflow.path (the names of the Steps leading to
+// a sub-workflow, joined with "/"), workflow.status and the number of Steps
+// by terminal status (workflow.steps.total, workflow.steps.succeeded, ...).
+// Sub-workflows inherit the hook and get their own workflow span. Under a
+// workflow span, each step span links to the step spans of its direct
+// upstreams, preserving the DAG in the trace.
+//
 // # Span conventions
 //
 // Step spans are named flow.String(step) and carry attributes
-// workflow.step.name and workflow.step.status ("success" or "error").
+// workflow.step.name and workflow.step.status, the terminal flow.StepStatus
+// ("Succeeded", "Failed", "Canceled" or "Skipped"); Skipped and Canceled
+// steps also carry workflow.step.reason.
 // Attempt spans are named "<step> (attempt N)" and carry workflow.step.name
 // and workflow.step.attempt (int64).
 //
@@ -56,15 +72,17 @@
 // # Skipped and Canceled-by-Condition steps
 //
 // Steps whose Condition resolves to Skipped or Canceled are settled inline
-// by the workflow scheduler and bypass the interceptor chain entirely. As
-// a result, they produce zero spans through this package. If you need to
-// observe terminal-by-condition statuses, watch the StepResult instead.
-//
-// # context.Canceled
-//
-// When the error returned by next() satisfies errors.Is(err, context.Canceled),
-// the resulting span is ended with RecordError + SetStatus(codes.Error) —
-// the same as any other error. There is no special-case suppression. Users
-// running graceful shutdowns who want different semantics can wrap the
-// returned interceptor.
+// by the workflow scheduler and bypass the interceptor chain entirely. The
+// interceptors can't see them; NewWorkflowHook emits a zero-duration step
+// span for each, with workflow.step.reason "condition" (or the error that
+// settled the Step, e.g. the drain reason).
+//
+// # Errors, cancellation and skips
+//
+// The error returned by next() is classified like the Workflow does (see
+// flow.StatusOf): errors wrapping flow.ErrSkip, flow.ErrCancel,
+// context.Canceled or context.DeadlineExceeded make a Skipped or Canceled
+// Step, and flow.ErrSucceed a Succeeded one. Any error is recorded with
+// RecordError, but only Failed steps and attempts get
+// SetStatus(codes.Error): a graceful shutdown doesn't paint the trace red.
 package flowotel
@@ -22,6 +22,7 @@ type config struct {
 	attemptSpanNamer  func(flow.Steper, uint64) string
 	stepAttributes    func(flow.Steper) []attribute.KeyValue
 	attemptAttributes func(flow.Steper, uint64) []attribute.KeyValue
+	workflowName      string
 }
 
 // Option configures a step or attempt interceptor produced by
@@ -124,6 +125,14 @@ func WithAttemptAttributes(fn func(flow.Steper, uint64) []attribute.KeyValue) Op
 	}
 }
 
+// WithWorkflowName sets the name of the top-level workflow span, and the
+// workflow.name attribute of every workflow span. Default: "workflow".
+//
+// Affects: NewWorkflowHook only. The interceptor factories ignore this option.
+func WithWorkflowName(name string) Option {
+	return func(c *config) { c.workflowName = name }
+}
+
 // resolveTracer picks the configured TracerProvider (falling back to the
 // global provider via otel.GetTracerProvider when nil) and returns a Tracer
 // with the configured (or default) instrumentation name. It is intended to be
@@ -15,18 +15,24 @@ import (
 // The span name defaults to flow.String(step) and may be overridden via
 // WithStepSpanNamer. The default attribute set always includes
 // workflow.step.name = flow.String(step) and, after next() returns,
-// workflow.step.status ∈ {"success", "error"}. Extra attributes can be
+// workflow.step.status, the terminal flow.StepStatus the error classifies to
+// ("Succeeded", "Failed", "Canceled" or "Skipped"). Extra attributes can be
 // supplied via WithStepAttributes; they are appended to (not in place of)
 // the defaults at span-start time. Canonical attributes (workflow.step.name,
 // workflow.step.status) always win over user-supplied attributes — i.e.,
 // WithStepAttributes cannot override them.
 //
 // On a non-nil error from next() the span records the error via
-// span.RecordError and sets its status to codes.Error. context.Canceled
-// is treated like any other error (no special-case).
+// span.RecordError. Only a Failed Step sets the span status to codes.Error:
+// Skipped and Canceled Steps (ErrSkip, ErrCancel, context.Canceled, ...) are
+// not errors, and carry workflow.step.reason, the error message.
+//
+// Under the workflow span of NewWorkflowHook, the span links to the step
+// spans of the Step's direct upstreams.
 //
 // Steps that the scheduler settles inline (Skipped or Canceled by their
-// Condition) bypass the interceptor chain entirely and produce no span.
+// Condition) bypass the interceptor chain entirely; NewWorkflowHook emits
+// zero-duration spans for them.
 func NewStepInterceptor(opts ...Option) flow.StepInterceptor {
 	cfg := newConfig(opts)
 	tracer := cfg.resolveTracer()
@@ -42,17 +48,45 @@ func NewStepInterceptor(opts ...Option) flow.StepInterceptor {
 			attrs = append(attrs, cfg.stepAttributes(step)...)
 		}
 		attrs = append(attrs, attribute.String(attrStepName, flow.String(step)))
-		ctx, span := tracer.Start(ctx, spanName, trace.WithAttributes(attrs...))
+		startOpts := []trace.SpanStartOption{trace.WithAttributes(attrs...)}
+		r := runFrom(ctx)
+		if r != nil && r.w.RootOf(step) != step {
+			r = nil // in a sub-workflow without its own workflow span
+		}
+		if r != nil {
+			startOpts = append(startOpts, trace.WithLinks(r.linksOf(step)...))
+		}
+		ctx, span := tracer.Start(ctx, spanName, startOpts...)
 		defer span.End()
+		if r != nil {
+			r.started(step, span)
+			ctx = context.WithValue(ctx, rootKey{}, step)
+		}
 
 		err := next(ctx)
-		if err != nil {
-			span.SetAttributes(attribute.String(attrStepStatus, statusError))
-			span.RecordError(err)
-			span.SetStatus(codes.Error, err.Error())
-		} else {
-			span.SetAttributes(attribute.String(attrStepStatus, statusSuccess))
-		}
+		recordOutcome(span, flow.StatusOf(err), err)
 		return err
 	})
 }
+
+// recordOutcome sets the workflow.step.status of a step span, and the
+// workflow.step.reason of a Skipped or Canceled Step. Any error is recorded,
+// but only a Failed Step marks the span as an error.
+func recordOutcome(span trace.Span, status flow.StepStatus, err error) {
+	span.SetAttributes(attribute.String(attrStepStatus, string(status)))
+	switch status {
+	case flow.Skipped, flow.Canceled:
+		reason := reasonCondition
+		if err != nil {
+			reason = err.Error()
+		}
+		span.SetAttributes(attribute.String(attrStepReason, reason))
+	}
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	if status == flow.Failed {
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -86,7 +86,7 @@ func TestStepInterceptor_SuccessOneSpan(t *testing.T) {
 	s := spans[0]
 	assert.Equal(t, "MyStep", s.Name())
 	assertAttr(t, s.Attributes(), "workflow.step.name", "MyStep")
-	assertAttr(t, s.Attributes(), "workflow.step.status", "success")
+	assertAttr(t, s.Attributes(), "workflow.step.status", "Succeeded")
 	assert.Equal(t, codes.Unset, s.Status().Code, "no SetStatus on success")
 }
 
@@ -102,7 +102,7 @@ func TestStepInterceptor_RetriesStillOneSpan(t *testing.T) {
 	spans := rec.Ended()
 	require.Len(t, spans, 1, "step interceptor must emit exactly one span across retries")
 	s := spans[0]
-	assertAttr(t, s.Attributes(), "workflow.step.status", "success")
+	assertAttr(t, s.Attributes(), "workflow.step.status", "Succeeded")
 }
 
 func TestStepInterceptor_FinalErrorRecorded(t *testing.T) {
@@ -118,7 +118,7 @@ func TestStepInterceptor_FinalErrorRecorded(t *testing.T) {
 	spans := rec.Ended()
 	require.Len(t, spans, 1)
 	s := spans[0]
-	assertAttr(t, s.Attributes(), "workflow.step.status", "error")
+	assertAttr(t, s.Attributes(), "workflow.step.status", "Failed")
 	assert.Equal(t, codes.Error, s.Status().Code)
 
 	events := s.Events()
@@ -143,7 +143,9 @@ func TestStepInterceptor_ContextCanceled(t *testing.T) {
 	spans := rec.Ended()
 	require.Len(t, spans, 1)
 	s := spans[0]
-	assert.Equal(t, codes.Error, s.Status().Code)
+	assertAttr(t, s.Attributes(), "workflow.step.status", "Canceled")
+	assertAttr(t, s.Attributes(), "workflow.step.reason", context.Canceled.Error())
+	assert.Equal(t, codes.Unset, s.Status().Code, "a canceled step is not an error")
 	var sawException bool
 	for _, ev := range s.Events() {
 		if ev.Name == exceptionEventName {
@@ -154,6 +156,30 @@ func TestStepInterceptor_ContextCanceled(t *testing.T) {
 	assert.True(t, sawException, "context.Canceled should record an exception event")
 }
 
+func TestStepInterceptor_StatusFromError(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		Err    error
+		Status string
+		Code   codes.Code
+	}{
+		{flow.Skip(errors.New("nothing to do")), "Skipped", codes.Unset},
+		{flow.Cancel(errors.New("stop")), "Canceled", codes.Unset},
+		{flow.Succeed(errors.New("done")), "Succeeded", codes.Unset},
+		{errors.New("boom"), "Failed", codes.Error},
+	} {
+		tp, rec := newRecorderTracerProvider()
+		w := newTestWorkflow(flowotel.NewStepInterceptor(flowotel.WithTracerProvider(tp)), nil)
+		w.Add(flow.Step(&alwaysFail{Name: "S", Err: tc.Err}))
+		_ = w.Do(context.Background())
+
+		spans := rec.Ended()
+		require.Len(t, spans, 1)
+		assertAttr(t, spans[0].Attributes(), "workflow.step.status", tc.Status)
+		assert.Equal(t, tc.Code, spans[0].Status().Code, "status code for %s", tc.Status)
+	}
+}
+
 func TestStepInterceptor_SkippedStepNoSpan(t *testing.T) {
 	t.Parallel()
 	tp, rec := newRecorderTracerProvider()
@@ -164,7 +190,7 @@ func TestStepInterceptor_SkippedStepNoSpan(t *testing.T) {
 	}))
 	require.NoError(t, w.Do(context.Background()))
 
-	assert.Empty(t, rec.Ended(), "Skipped steps must bypass the interceptor chain")
+	assert.Empty(t, rec.Ended(), "Skipped steps must bypass the interceptor chain, only NewWorkflowHook traces them")
 	// neither Started() nor Ended() should fire for a Skipped step.
 	assert.Empty(t, rec.Started())
 }
@@ -209,9 +235,9 @@ func TestStepInterceptor_CustomAttributesAppend(t *testing.T) {
 	spans := rec.Ended()
 	require.Len(t, spans, 1)
 	attrs := spans[0].Attributes()
-	assertAttr(t, attrs, "workflow.step.name", "Hello")     // default still present
-	assertAttr(t, attrs, "workflow.step.status", "success") // default still present
-	assertAttr(t, attrs, "env", "test")                     // user-supplied
+	assertAttr(t, attrs, "workflow.step.name", "Hello")       // default still present
+	assertAttr(t, attrs, "workflow.step.status", "Succeeded") // default still present
+	assertAttr(t, attrs, "env", "test")                       // user-supplied
 	a, ok := findAttr(attrs, "answer")
 	require.True(t, ok, "custom int attribute missing")
 	assert.Equal(t, int64(42), a.Value.AsInt64())
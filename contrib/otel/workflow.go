@@ -0,0 +1,213 @@
+package flowotel
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	flow "github.com/Azure/go-workflow"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultWorkflowName is the name of the top-level workflow span when
+// WithWorkflowName is not used.
+const defaultWorkflowName = "workflow"
+
+// NewWorkflowHook returns a flow.WorkflowHook that wraps every Do of a
+// Workflow in a workflow span, the parent of the spans of its Steps:
+//
+//	w.Option.Hooks = append(w.Option.Hooks, flowotel.NewWorkflowHook(flowotel.WithTracerProvider(tp)))
+//
+// The hook is inherited, so every sub-workflow gets its own workflow span,
+// a child of the span of the Step containing it. The span of the top-level
+// workflow is named after WithWorkflowName ("workflow" by default); the span
+// of a sub-workflow is named after its path, the names of the Steps leading
+// to it joined with "/", e.g. "deploy/Region-1". A sub-workflow embedded in
+// a struct (struct{ flow.Workflow }) is found through the step span of its
+// root Step, so install NewStepInterceptor as well.
+//
+// Workflow spans carry workflow.name, workflow.path and, when the run ends,
+// workflow.status ∈ {"success", "error"}, workflow.steps.total and the number
+// of Steps by terminal status: workflow.steps.succeeded, .failed, .canceled
+// and .skipped. A run ending with an error records it on the span and sets
+// its status to codes.Error.
+//
+// Steps the scheduler settles without running them, Skipped or Canceled by
+// their Condition or canceled by a drain, bypass the interceptors. The hook
+// emits a zero-duration span for each of them, a child of the workflow span
+// at the time the Step was settled, with the same name and attributes as the
+// spans of NewStepInterceptor. The span is emitted once a downstream links
+// to it, or when the run ends.
+//
+// While a workflow span is on the context, the step spans emitted by
+// NewStepInterceptor link to the step spans of their direct upstreams (see
+// Workflow.UpstreamOf), so the trace keeps the shape of the DAG.
+func NewWorkflowHook(opts ...Option) flow.WorkflowHook {
+	cfg := newConfig(opts)
+	tracer := cfg.resolveTracer()
+	name := cfg.workflowName
+	if name == "" {
+		name = defaultWorkflowName
+	}
+	return flow.WorkflowHook{
+		OnStart: func(ctx context.Context, w *flow.Workflow) (context.Context, error) {
+			r := &run{
+				w:      w,
+				path:   []string{name},
+				tracer: tracer,
+				cfg:    cfg,
+				spans:  make(map[flow.Steper]trace.SpanContext),
+			}
+			if parent := runFrom(ctx); parent != nil {
+				r.path = append(append([]string{}, parent.path...), parent.segmentOf(ctx, w))
+			}
+			path := strings.Join(r.path, "/")
+			ctx, r.span = tracer.Start(ctx, path, trace.WithAttributes(
+				attribute.String(attrWorkflowName, name),
+				attribute.String(attrWorkflowPath, path),
+			))
+			return context.WithValue(ctx, runKey{}, r), nil
+		},
+		Finally: func(ctx context.Context, w *flow.Workflow, err error) error {
+			// OnStart may not have run, if an earlier hook failed.
+			r := runFrom(ctx)
+			if r == nil || r.w != w {
+				return err
+			}
+			r.traceSettled()
+			r.span.SetAttributes(stepCounts(w)...)
+			if err != nil {
+				r.span.SetAttributes(attribute.String(attrWorkflowStatus, statusError))
+				r.span.RecordError(err)
+				r.span.SetStatus(codes.Error, err.Error())
+			} else {
+				r.span.SetAttributes(attribute.String(attrWorkflowStatus, statusSuccess))
+			}
+			r.span.End()
+			return err
+		},
+		Inherit: true,
+	}
+}
+
+// run is the per-Do state of a workflow span, carried on the context.
+type run struct {
+	w      *flow.Workflow
+	path   []string
+	span   trace.Span
+	tracer trace.Tracer
+	cfg    *config
+
+	mu    sync.Mutex
+	spans map[flow.Steper]trace.SpanContext // step span of each root Step started so far
+}
+
+type runKey struct{}
+
+func runFrom(ctx context.Context) *run {
+	r, _ := ctx.Value(runKey{}).(*run)
+	return r
+}
+
+// rootKey carries the root Step whose step span is on the context, set by
+// NewStepInterceptor.
+type rootKey struct{}
+
+// segmentOf names the sub-workflow sub, run with ctx, in the path: after the
+// root Step of r.w containing it. That's the root Step on ctx, as a Workflow
+// embedded in a struct isn't in its Unwrap tree; without a step interceptor,
+// it's looked up in r.w.
+func (r *run) segmentOf(ctx context.Context, sub *flow.Workflow) string {
+	if root, ok := ctx.Value(rootKey{}).(flow.Steper); ok && r.w.RootOf(root) == root {
+		return flow.String(root)
+	}
+	if root := r.w.RootOf(sub); root != nil {
+		return flow.String(root)
+	}
+	return flow.String(sub)
+}
+
+// started records the span of step, a root Step of r.w.
+func (r *run) started(step flow.Steper, span trace.Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans[step] = span.SpanContext()
+}
+
+// linksOf returns links to the spans of the direct upstreams of step,
+// tracing the upstreams settled without running on the way.
+func (r *run) linksOf(step flow.Steper) []trace.Link {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.linksLocked(step)
+}
+
+func (r *run) linksLocked(step flow.Steper) []trace.Link {
+	var links []trace.Link
+	for up := range r.w.UpstreamOf(step) {
+		sc, ok := r.spans[up]
+		if !ok {
+			sc, ok = r.settleLocked(up)
+		}
+		if ok {
+			links = append(links, trace.Link{SpanContext: sc})
+		}
+	}
+	return links
+}
+
+// settleLocked emits a zero-duration step span for step, a root Step of r.w
+// that terminated without a step span, i.e. without running. It returns
+// false if step hasn't terminated yet.
+func (r *run) settleLocked(step flow.Steper) (trace.SpanContext, bool) {
+	result := r.w.StateOf(step).GetStepResult()
+	if !result.Status.IsTerminated() {
+		return trace.SpanContext{}, false
+	}
+	spanName := flow.String(step)
+	if r.cfg.stepSpanNamer != nil {
+		spanName = r.cfg.stepSpanNamer(step)
+	}
+	var attrs []attribute.KeyValue
+	if r.cfg.stepAttributes != nil {
+		attrs = append(attrs, r.cfg.stepAttributes(step)...)
+	}
+	attrs = append(attrs, attribute.String(attrStepName, flow.String(step)))
+	_, span := r.tracer.Start(trace.ContextWithSpan(context.Background(), r.span), spanName,
+		trace.WithAttributes(attrs...),
+		trace.WithLinks(r.linksLocked(step)...),
+		trace.WithTimestamp(result.FinishedAt),
+	)
+	recordOutcome(span, result.Status, result.Err)
+	span.End(trace.WithTimestamp(result.FinishedAt))
+	r.spans[step] = span.SpanContext()
+	return r.spans[step], true
+}
+
+// traceSettled emits the step spans of the Steps settled without running
+// that no downstream has linked to yet.
+func (r *run) traceSettled() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, step := range r.w.Steps() {
+		if _, ok := r.spans[step]; !ok {
+			r.settleLocked(step)
+		}
+	}
+}
+
+// stepCounts returns the workflow.steps.* attributes of w.
+func stepCounts(w *flow.Workflow) []attribute.KeyValue {
+	counts := make(map[flow.StepStatus]int)
+	steps := w.Steps()
+	for _, step := range steps {
+		counts[w.StateOf(step).GetStatus()]++
+	}
+	attrs := []attribute.KeyValue{attribute.Int(attrWorkflowSteps+".total", len(steps))}
+	for _, status := range []flow.StepStatus{flow.Succeeded, flow.Failed, flow.Canceled, flow.Skipped} {
+		attrs = append(attrs, attribute.Int(attrWorkflowSteps+"."+strings.ToLower(string(status)), counts[status]))
+	}
+	return attrs
+}
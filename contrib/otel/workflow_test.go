@@ -0,0 +1,196 @@
+package flowotel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/Azure/go-workflow/contrib/otel"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTracedWorkflow builds an empty Workflow with the workflow hook and the
+// step interceptor wired to tp.
+func newTracedWorkflow(tp trace.TracerProvider, opts ...flowotel.Option) *flow.Workflow {
+	opts = append([]flowotel.Option{flowotel.WithTracerProvider(tp)}, opts...)
+	w := newTestWorkflow(flowotel.NewStepInterceptor(opts...), nil)
+	w.Option.Hooks = []flow.WorkflowHook{flowotel.NewWorkflowHook(opts...)}
+	return w
+}
+
+// spanNamed returns the ended span with the given name.
+func spanNamed(t *testing.T, rec *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for _, s := range rec.Ended() {
+		if s.Name() == name {
+			return s
+		}
+	}
+	require.Failf(t, "span not found", "no ended span named %q", name)
+	return nil
+}
+
+func assertIntAttr(t *testing.T, s sdktrace.ReadOnlySpan, key string, want int64) {
+	t.Helper()
+	a, ok := findAttr(s.Attributes(), key)
+	if assert.True(t, ok, "attribute %q not found", key) {
+		assert.Equal(t, want, a.Value.AsInt64(), "attribute %q value mismatch", key)
+	}
+}
+
+func TestWorkflowHook_RootSpanParentsSteps(t *testing.T) {
+	t.Parallel()
+	tp, rec := newRecorderTracerProvider()
+	w := newTracedWorkflow(tp, flowotel.WithWorkflowName("deploy"))
+	w.Add(flow.Steps(flow.NoOp("A"), flow.NoOp("B")))
+	require.NoError(t, w.Do(context.Background()))
+
+	require.Len(t, rec.Ended(), 3)
+	root := spanNamed(t, rec, "deploy")
+	assert.False(t, root.Parent().IsValid(), "workflow span is the root")
+	assertAttr(t, root.Attributes(), "workflow.name", "deploy")
+	assertAttr(t, root.Attributes(), "workflow.path", "deploy")
+	assertAttr(t, root.Attributes(), "workflow.status", "success")
+	assertIntAttr(t, root, "workflow.steps.total", 2)
+	assertIntAttr(t, root, "workflow.steps.succeeded", 2)
+	assertIntAttr(t, root, "workflow.steps.failed", 0)
+	for _, name := range []string{"A", "B"} {
+		assert.Equal(t, root.SpanContext().SpanID(), spanNamed(t, rec, name).Parent().SpanID(),
+			"step %s must be a child of the workflow span", name)
+	}
+}
+
+func TestWorkflowHook_LinksToUpstreams(t *testing.T) {
+	t.Parallel()
+	tp, rec := newRecorderTracerProvider()
+	w := newTracedWorkflow(tp)
+	a, b, c := flow.NoOp("A"), flow.NoOp("B"), flow.NoOp("C")
+	w.Add(
+		flow.Step(b).DependsOn(a),
+		flow.Step(c).DependsOn(a, b),
+	)
+	require.NoError(t, w.Do(context.Background()))
+
+	linked := func(name string) []trace.SpanID {
+		var ids []trace.SpanID
+		for _, l := range spanNamed(t, rec, name).Links() {
+			ids = append(ids, l.SpanContext.SpanID())
+		}
+		return ids
+	}
+	spanA, spanB := spanNamed(t, rec, "A"), spanNamed(t, rec, "B")
+	assert.Empty(t, linked("A"))
+	assert.Equal(t, []trace.SpanID{spanA.SpanContext().SpanID()}, linked("B"))
+	assert.ElementsMatch(t, []trace.SpanID{spanA.SpanContext().SpanID(), spanB.SpanContext().SpanID()}, linked("C"))
+}
+
+func TestWorkflowHook_Failure(t *testing.T) {
+	t.Parallel()
+	tp, rec := newRecorderTracerProvider()
+	w := newTracedWorkflow(tp)
+	fail := &alwaysFail{Name: "Fail", Err: errors.New("boom")}
+	w.Add(
+		flow.Step(flow.NoOp("OK")),
+		flow.Step(fail),
+	)
+	require.Error(t, w.Do(context.Background()))
+
+	root := spanNamed(t, rec, "workflow")
+	assertAttr(t, root.Attributes(), "workflow.status", "error")
+	assert.Equal(t, codes.Error, root.Status().Code)
+	assertIntAttr(t, root, "workflow.steps.succeeded", 1)
+	assertIntAttr(t, root, "workflow.steps.failed", 1)
+}
+
+func TestWorkflowHook_SubWorkflowPath(t *testing.T) {
+	t.Parallel()
+	tp, rec := newRecorderTracerProvider()
+	w := newTracedWorkflow(tp, flowotel.WithWorkflowName("deploy"))
+	inner := new(flow.Workflow)
+	inner.Add(flow.Step(flow.NoOp("Inner")))
+	w.Add(flow.Name(inner, "Region"))
+	require.NoError(t, w.Do(context.Background()))
+
+	require.Len(t, rec.Ended(), 4)
+	sub := spanNamed(t, rec, "deploy/Region")
+	assertAttr(t, sub.Attributes(), "workflow.name", "deploy")
+	assertAttr(t, sub.Attributes(), "workflow.path", "deploy/Region")
+	assertIntAttr(t, sub, "workflow.steps.total", 1)
+	assert.Equal(t, spanNamed(t, rec, "Region").SpanContext().SpanID(), sub.Parent().SpanID(),
+		"sub-workflow span is a child of the span of its step")
+	assert.Equal(t, sub.SpanContext().SpanID(), spanNamed(t, rec, "Inner").Parent().SpanID())
+}
+
+// region is a sub-workflow embedding flow.Workflow, whose Workflow isn't in
+// the Unwrap tree of the root Step.
+type region struct{ flow.Workflow }
+
+func TestWorkflowHook_EmbeddedSubWorkflowPath(t *testing.T) {
+	t.Parallel()
+	tp, rec := newRecorderTracerProvider()
+	w := newTracedWorkflow(tp, flowotel.WithWorkflowName("deploy"))
+	inner := new(region)
+	inner.Add(flow.Step(flow.NoOp("Inner")))
+	w.Add(flow.Name(inner, "Region"))
+	require.NoError(t, w.Do(context.Background()))
+
+	sub := spanNamed(t, rec, "deploy/Region")
+	assertAttr(t, sub.Attributes(), "workflow.path", "deploy/Region")
+	assert.Equal(t, spanNamed(t, rec, "Region").SpanContext().SpanID(), sub.Parent().SpanID())
+}
+
+func TestWorkflowHook_AbortedByEarlierHook(t *testing.T) {
+	t.Parallel()
+	tp, rec := newRecorderTracerProvider()
+	w := newTracedWorkflow(tp)
+	abort := errors.New("abort")
+	w.Option.Hooks = append([]flow.WorkflowHook{{
+		OnStart: func(ctx context.Context, _ *flow.Workflow) (context.Context, error) { return ctx, abort },
+	}}, w.Option.Hooks...)
+	w.Add(flow.Step(flow.NoOp("A")))
+	assert.ErrorIs(t, w.Do(context.Background()), abort)
+	assert.Empty(t, rec.Started())
+}
+
+func TestWorkflowHook_TracesSettledSteps(t *testing.T) {
+	t.Parallel()
+	tp, rec := newRecorderTracerProvider()
+	w := newTracedWorkflow(tp)
+	fail := &alwaysFail{Name: "Fail", Err: errors.New("boom")}
+	after, afterAfter := flow.NoOp("After"), flow.NoOp("AfterAfter")
+	w.Add(
+		flow.Step(after).DependsOn(fail),
+		flow.Step(afterAfter).DependsOn(after).When(flow.Always),
+		flow.Step(flow.NoOp("Cancel")).When(func(context.Context, map[flow.Steper]flow.StepResult) flow.StepStatus {
+			return flow.Canceled
+		}),
+	)
+	require.Error(t, w.Do(context.Background()))
+
+	spanAfter := spanNamed(t, rec, "After")
+	assertAttr(t, spanAfter.Attributes(), "workflow.step.status", "Skipped")
+	assertAttr(t, spanAfter.Attributes(), "workflow.step.reason", "condition")
+	assert.Equal(t, codes.Unset, spanAfter.Status().Code)
+	assert.Equal(t, spanAfter.StartTime(), spanAfter.EndTime(), "settled steps have zero-duration spans")
+	require.Len(t, spanAfter.Links(), 1)
+	assert.Equal(t, spanNamed(t, rec, "Fail").SpanContext().SpanID(), spanAfter.Links()[0].SpanContext.SpanID())
+
+	spanAfterAfter := spanNamed(t, rec, "AfterAfter")
+	require.Len(t, spanAfterAfter.Links(), 1, "links to settled upstreams too")
+	assert.Equal(t, spanAfter.SpanContext().SpanID(), spanAfterAfter.Links()[0].SpanContext.SpanID())
+
+	spanCancel := spanNamed(t, rec, "Cancel")
+	assertAttr(t, spanCancel.Attributes(), "workflow.step.status", "Canceled")
+	assert.Equal(t, codes.Unset, spanCancel.Status().Code)
+	root := spanNamed(t, rec, "workflow")
+	assert.Equal(t, root.SpanContext().SpanID(), spanCancel.Parent().SpanID())
+	assertIntAttr(t, root, "workflow.steps.skipped", 1)
+	assertIntAttr(t, root, "workflow.steps.canceled", 1)
+}
@@ -0,0 +1,43 @@
+// Package flowprom exposes go-workflow metrics through Prometheus
+// collectors, without OpenTelemetry.
+//
+// A Metrics is both a prometheus.Collector and the StepInterceptor /
+// AttemptInterceptor pair feeding it:
+//
+//	import (
+//	    flow "github.com/Azure/go-workflow"
+//	    flowprom "github.com/Azure/go-workflow/contrib/prometheus"
+//	    "github.com/prometheus/client_golang/prometheus"
+//	)
+//
+//	metrics := flowprom.New(flowprom.WithNamespace("deploy"))
+//	prometheus.MustRegister(metrics)
+//	metrics.Install(w)
+//
+// Sub-workflows inherit the interceptors, so their Steps are measured too.
+//
+// # Metrics
+//
+//	workflow_steps_total{step,status}              counter
+//	workflow_step_duration_seconds{step,status}    histogram, whole lifetime (all attempts)
+//	workflow_steps_in_flight{step}                 gauge
+//	workflow_step_attempts_total{step,result}      counter, result is "success" or "error"
+//	workflow_step_attempt_duration_seconds{step}   histogram
+//
+// status is the Step's terminal flow.StepStatus ("Succeeded", "Failed",
+// "Canceled", "Skipped"), and result is "success" for the attempts whose
+// flow.StatusOf is Succeeded or Skipped. The names are prefixed by
+// WithNamespace, if set.
+//
+// # Cardinality
+//
+// The step label defaults to flow.String(step), which is only bounded if
+// Steps have stable names (Func, flow.Name, a String method): Steps
+// rendered with their pointer address make a new series per instance. Use
+// WithLabeler to map Steps to a bounded set of values, e.g. their type.
+//
+// # Skipped and Canceled-by-Condition steps
+//
+// Steps whose Condition resolves to Skipped or Canceled are settled inline
+// by the scheduler and bypass the interceptor chain: they are not counted.
+package flowprom
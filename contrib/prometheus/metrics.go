@@ -0,0 +1,135 @@
+package flowprom
+
+import (
+	"context"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/benbjohnson/clock"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Label names and attempt results.
+const (
+	labelStep   = "step"
+	labelStatus = "status"
+	labelResult = "result"
+
+	resultSuccess = "success"
+	resultError   = "error"
+)
+
+// Metrics collects workflow metrics; see the package documentation. It is
+// safe for concurrent use, and one Metrics may be installed on any number
+// of Workflows.
+type Metrics struct {
+	clock   clock.Clock
+	labeler func(flow.Steper) string
+
+	steps           *prometheus.CounterVec
+	stepDuration    *prometheus.HistogramVec
+	inFlight        *prometheus.GaugeVec
+	attempts        *prometheus.CounterVec
+	attemptDuration *prometheus.HistogramVec
+}
+
+// New returns a Metrics configured by opts.
+func New(opts ...Option) *Metrics {
+	c := newConfig(opts)
+	m := &Metrics{clock: c.clock, labeler: c.labeler}
+	m.steps = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   c.namespace,
+		Name:        "workflow_steps_total",
+		Help:        "Steps that terminated, by terminal status.",
+		ConstLabels: c.constLabels,
+	}, []string{labelStep, labelStatus})
+	m.stepDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   c.namespace,
+		Name:        "workflow_step_duration_seconds",
+		Help:        "Duration of Steps, across all their attempts, by terminal status.",
+		ConstLabels: c.constLabels,
+		Buckets:     c.buckets,
+	}, []string{labelStep, labelStatus})
+	m.inFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   c.namespace,
+		Name:        "workflow_steps_in_flight",
+		Help:        "Steps currently running.",
+		ConstLabels: c.constLabels,
+	}, []string{labelStep})
+	m.attempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   c.namespace,
+		Name:        "workflow_step_attempts_total",
+		Help:        "Step attempts, by result.",
+		ConstLabels: c.constLabels,
+	}, []string{labelStep, labelResult})
+	m.attemptDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   c.namespace,
+		Name:        "workflow_step_attempt_duration_seconds",
+		Help:        "Duration of Step attempts.",
+		ConstLabels: c.constLabels,
+		Buckets:     c.buckets,
+	}, []string{labelStep})
+	return m
+}
+
+// Install appends m to w.Option.StepInterceptors and AttemptInterceptors.
+func (m *Metrics) Install(w *flow.Workflow) {
+	w.Option.StepInterceptors = append(w.Option.StepInterceptors, m)
+	w.Option.AttemptInterceptors = append(w.Option.AttemptInterceptors, m)
+}
+
+func (m *Metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.steps, m.stepDuration, m.inFlight, m.attempts, m.attemptDuration}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range m.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range m.collectors() {
+		c.Collect(ch)
+	}
+}
+
+// InterceptStep implements flow.StepInterceptor: it counts the Step, its
+// duration and terminal status, and tracks it as in flight meanwhile.
+func (m *Metrics) InterceptStep(ctx context.Context, step flow.Steper, next func(context.Context) error) error {
+	label := m.labeler(step)
+	inFlight := m.inFlight.WithLabelValues(label)
+	inFlight.Inc()
+	defer inFlight.Dec()
+
+	start := m.clock.Now()
+	err := next(ctx)
+	status := string(flow.StatusOf(err))
+	m.steps.WithLabelValues(label, status).Inc()
+	m.stepDuration.WithLabelValues(label, status).Observe(m.clock.Since(start).Seconds())
+	return err
+}
+
+// InterceptAttempt implements flow.AttemptInterceptor: it counts the
+// attempt, its result and duration. An attempt returning nil, or an error
+// marked by flow.Succeed or flow.Skip, is a success.
+func (m *Metrics) InterceptAttempt(ctx context.Context, step flow.Steper, attempt uint64, next func(context.Context) error) error {
+	label := m.labeler(step)
+	start := m.clock.Now()
+	err := next(ctx)
+	result := resultError
+	switch flow.StatusOf(err) {
+	case flow.Succeeded, flow.Skipped:
+		result = resultSuccess
+	}
+	m.attempts.WithLabelValues(label, result).Inc()
+	m.attemptDuration.WithLabelValues(label).Observe(m.clock.Since(start).Seconds())
+	return err
+}
+
+var (
+	_ prometheus.Collector    = (*Metrics)(nil)
+	_ flow.StepInterceptor    = (*Metrics)(nil)
+	_ flow.AttemptInterceptor = (*Metrics)(nil)
+)
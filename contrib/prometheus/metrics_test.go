@@ -0,0 +1,161 @@
+package flowprom_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	flow "github.com/Azure/go-workflow"
+	flowprom "github.com/Azure/go-workflow/contrib/prometheus"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flaky fails until its Nth attempt.
+type flaky struct {
+	Name         string
+	NeedAttempts int
+	Attempts     int
+}
+
+func (s *flaky) String() string { return s.Name }
+func (s *flaky) Do(context.Context) error {
+	s.Attempts++
+	if s.Attempts < s.NeedAttempts {
+		return errors.New("transient")
+	}
+	return nil
+}
+
+func noBackoff(attempts uint64) func(*flow.RetryOption) {
+	return func(ro *flow.RetryOption) {
+		ro.Attempts = attempts
+		ro.Backoff = &backoff.ZeroBackOff{}
+	}
+}
+
+func TestMetrics_Steps(t *testing.T) {
+	t.Parallel()
+	m := flowprom.New()
+	w := new(flow.Workflow)
+	m.Install(w)
+	a, b := flow.NoOp("A"), flow.NoOp("B")
+	c := flow.Func("C", func(context.Context) error { return errors.New("boom") })
+	w.Add(
+		flow.Step(b).DependsOn(a),
+		flow.Step(c),
+	)
+	require.Error(t, w.Do(context.Background()))
+
+	require.NoError(t, testutil.CollectAndCompare(m, strings.NewReader(`
+# HELP workflow_steps_total Steps that terminated, by terminal status.
+# TYPE workflow_steps_total counter
+workflow_steps_total{status="Failed",step="C"} 1
+workflow_steps_total{status="Succeeded",step="A"} 1
+workflow_steps_total{status="Succeeded",step="B"} 1
+# HELP workflow_steps_in_flight Steps currently running.
+# TYPE workflow_steps_in_flight gauge
+workflow_steps_in_flight{step="A"} 0
+workflow_steps_in_flight{step="B"} 0
+workflow_steps_in_flight{step="C"} 0
+`), "workflow_steps_total", "workflow_steps_in_flight"))
+	assert.Equal(t, 3, testutil.CollectAndCount(m, "workflow_step_duration_seconds"))
+}
+
+func TestMetrics_Attempts(t *testing.T) {
+	t.Parallel()
+	m := flowprom.New(flowprom.WithNamespace("test"))
+	w := new(flow.Workflow)
+	m.Install(w)
+	step := &flaky{Name: "Flaky", NeedAttempts: 3}
+	w.Add(flow.Step(step).Retry(noBackoff(5)))
+	require.NoError(t, w.Do(context.Background()))
+
+	require.NoError(t, testutil.CollectAndCompare(m, strings.NewReader(`
+# HELP test_workflow_step_attempts_total Step attempts, by result.
+# TYPE test_workflow_step_attempts_total counter
+test_workflow_step_attempts_total{result="error",step="Flaky"} 2
+test_workflow_step_attempts_total{result="success",step="Flaky"} 1
+# HELP test_workflow_steps_total Steps that terminated, by terminal status.
+# TYPE test_workflow_steps_total counter
+test_workflow_steps_total{status="Succeeded",step="Flaky"} 1
+`), "test_workflow_step_attempts_total", "test_workflow_steps_total"))
+}
+
+func TestMetrics_AttemptsSucceedAndSkip(t *testing.T) {
+	t.Parallel()
+	m := flowprom.New()
+	w := new(flow.Workflow)
+	m.Install(w)
+	w.Add(
+		flow.Step(flow.Func("Succeed", func(context.Context) error { return flow.Succeed(errors.New("already done")) })),
+		flow.Step(flow.Func("Skip", func(context.Context) error { return flow.Skip(errors.New("nothing to do")) })),
+	)
+	require.NoError(t, w.Do(context.Background()))
+
+	require.NoError(t, testutil.CollectAndCompare(m, strings.NewReader(`
+# HELP workflow_step_attempts_total Step attempts, by result.
+# TYPE workflow_step_attempts_total counter
+workflow_step_attempts_total{result="success",step="Skip"} 1
+workflow_step_attempts_total{result="success",step="Succeed"} 1
+# HELP workflow_steps_total Steps that terminated, by terminal status.
+# TYPE workflow_steps_total counter
+workflow_steps_total{status="Skipped",step="Skip"} 1
+workflow_steps_total{status="Succeeded",step="Succeed"} 1
+`), "workflow_step_attempts_total", "workflow_steps_total"))
+}
+
+func TestMetrics_Canceled(t *testing.T) {
+	t.Parallel()
+	m := flowprom.New()
+	w := new(flow.Workflow)
+	m.Install(w)
+	w.Add(flow.Step(flow.Func("Cancel", func(context.Context) error { return context.Canceled })))
+	require.Error(t, w.Do(context.Background()))
+
+	require.NoError(t, testutil.CollectAndCompare(m, strings.NewReader(`
+# HELP workflow_steps_total Steps that terminated, by terminal status.
+# TYPE workflow_steps_total counter
+workflow_steps_total{status="Canceled",step="Cancel"} 1
+`), "workflow_steps_total"))
+}
+
+func TestMetrics_Labeler(t *testing.T) {
+	t.Parallel()
+	m := flowprom.New(flowprom.WithLabeler(func(flow.Steper) string { return "any" }))
+	w := new(flow.Workflow)
+	m.Install(w)
+	w.Add(flow.Steps(flow.NoOp("A"), flow.NoOp("B")))
+	require.NoError(t, w.Do(context.Background()))
+
+	require.NoError(t, testutil.CollectAndCompare(m, strings.NewReader(`
+# HELP workflow_steps_total Steps that terminated, by terminal status.
+# TYPE workflow_steps_total counter
+workflow_steps_total{status="Succeeded",step="any"} 2
+`), "workflow_steps_total"))
+}
+
+func TestMetrics_SubWorkflow(t *testing.T) {
+	t.Parallel()
+	m := flowprom.New()
+	w := new(flow.Workflow)
+	m.Install(w)
+	inner := new(flow.Workflow)
+	inner.Add(flow.Step(flow.NoOp("Inner")))
+	w.Add(flow.Step(inner))
+	require.NoError(t, w.Do(context.Background()))
+
+	assert.Equal(t, 2, testutil.CollectAndCount(m, "workflow_steps_total"),
+		"both the sub-workflow and its inner step are counted")
+}
+
+func TestMetrics_Register(t *testing.T) {
+	t.Parallel()
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(flowprom.New(flowprom.WithConstLabels(prometheus.Labels{"workflow": "deploy"}))))
+}
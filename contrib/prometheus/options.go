@@ -0,0 +1,76 @@
+package flowprom
+
+import (
+	flow "github.com/Azure/go-workflow"
+	"github.com/benbjohnson/clock"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// config is the resolved configuration of a Metrics.
+type config struct {
+	namespace   string
+	constLabels prometheus.Labels
+	buckets     []float64
+	labeler     func(flow.Steper) string
+	clock       clock.Clock
+}
+
+// Option configures a Metrics created by New.
+type Option func(*config)
+
+// newConfig applies opts to the default config and returns it. nil options
+// are skipped so callers can build slices conditionally.
+func newConfig(opts []Option) *config {
+	c := &config{
+		buckets: prometheus.DefBuckets,
+		labeler: flow.String,
+		clock:   clock.New(),
+	}
+	for _, o := range opts {
+		if o != nil {
+			o(c)
+		}
+	}
+	return c
+}
+
+// WithNamespace prefixes every metric name with namespace, e.g.
+// "deploy_workflow_steps_total".
+func WithNamespace(namespace string) Option {
+	return func(c *config) { c.namespace = namespace }
+}
+
+// WithConstLabels attaches labels with fixed values to every metric, e.g. the
+// name of the Workflow the Metrics is installed on.
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(c *config) { c.constLabels = labels }
+}
+
+// WithBuckets overrides the histogram buckets, in seconds. Default:
+// prometheus.DefBuckets. Passing no buckets is a no-op.
+func WithBuckets(buckets ...float64) Option {
+	return func(c *config) {
+		if len(buckets) > 0 {
+			c.buckets = buckets
+		}
+	}
+}
+
+// WithLabeler overrides the value of the step label, flow.String(step) by
+// default. Use it to keep cardinality bounded. Passing a nil fn is a no-op.
+func WithLabeler(fn func(flow.Steper) string) Option {
+	return func(c *config) {
+		if fn != nil {
+			c.labeler = fn
+		}
+	}
+}
+
+// WithClock sets the clock used to measure durations, for tests.
+func WithClock(clock clock.Clock) Option {
+	return func(c *config) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// ErrSoftTimeout is the StepResult.Err of the Steps a Workflow didn't start
+// because its soft deadline (Option.Deadline or Option.SoftTimeout) passed;
+// they are settled Canceled.
+var ErrSoftTimeout = errors.New("workflow soft timeout: step not started")
+
+// ErrHardTimeout is the cancellation cause of running Steps' context once
+// the grace period after the Workflow started draining (Option.HardTimeout,
+// or the grace of RunWithSignals) expired.
+var ErrHardTimeout = errors.New("workflow hard timeout: step canceled")
+
+// ErrDrained is the StepResult.Err of the Steps a Workflow didn't start
+// because Workflow.Drain was called without a reason.
+var ErrDrained = errors.New("workflow drained: step not started")
+
+// Drain makes the running Workflow stop starting new Steps, as if its soft
+// deadline passed: Pending Steps are settled Canceled with reason (or
+// ErrDrained) as StepResult.Err, running Steps go on, subject to
+// Option.HardTimeout, and sub-workflows drain with it. Cancel the context
+// passed to Do to stop the running Steps too.
+//
+// Drain reports whether it took effect: it is a no-op if the Workflow is
+// not running or is already draining.
+func (w *Workflow) Drain(reason error) bool {
+	d := w.drain.Load()
+	if d == nil {
+		return false
+	}
+	if reason == nil {
+		reason = ErrDrained
+	}
+	return d.begin(reason)
+}
+
+// drainer makes a Workflow stop starting new Steps during one Do, because
+// of Option.Deadline / SoftTimeout, Workflow.Drain or a draining parent;
+// it then cancels running Steps after the grace period.
+type drainer struct {
+	w       *Workflow     // nil if the drainer only propagates, see RunWithSignals.
+	drained chan struct{} // closed once draining
+	stopped chan struct{} // closed when Do returns
+
+	mu     sync.Mutex
+	reason error                   // StepResult.Err of the Steps not started.
+	armed  bool                    // arm was called.
+	cancel context.CancelCauseFunc // cancels the Steps' context after Option.HardTimeout; nil without.
+	timers []*clock.Timer
+	closed bool
+}
+
+// drainKey stores the drainer of the Workflow running a Step in its
+// context, so sub-workflows drain with their parent.
+var drainKey = ContextKey[*drainer]{}
+
+func newDrainer(w *Workflow) *drainer {
+	return &drainer{w: w, drained: make(chan struct{}), stopped: make(chan struct{})}
+}
+
+// softDeadline returns when the Workflow started at start must stop starting
+// new Steps, and whether it has a soft deadline at all.
+func (w *Workflow) softDeadline(start time.Time) (time.Time, bool) {
+	var at time.Time
+	if w.Option.Deadline != nil {
+		at = *w.Option.Deadline
+	}
+	if w.Option.SoftTimeout != nil {
+		if soft := start.Add(*w.Option.SoftTimeout); at.IsZero() || soft.Before(at) {
+			at = soft
+		}
+	}
+	return at, !at.IsZero()
+}
+
+// arm is called when scheduling starts: it arms the soft deadline and the
+// drain of the parent Workflow, and returns the context to run the Steps
+// with. That context is only derived from ctx when needed: to cancel it on
+// Option.HardTimeout, or for sub-workflows (nested) to drain with w.
+func (d *drainer) arm(ctx context.Context, nested bool) context.Context {
+	w := d.w
+	parent, hasParent := drainKey.From(ctx)
+	d.mu.Lock()
+	d.armed = true
+	if w.Option.HardTimeout != nil {
+		ctx, d.cancel = context.WithCancelCause(ctx)
+	}
+	if d.isDrained() {
+		d.armHardTimeout()
+	}
+	d.mu.Unlock()
+
+	if hasParent {
+		select {
+		case <-parent.drained: // before any Step started.
+			d.begin(parent.drainReason())
+		default:
+			go func() {
+				select {
+				case <-parent.drained:
+					d.begin(parent.drainReason())
+				case <-d.stopped:
+				}
+			}()
+		}
+	}
+	now := w.clock().Now()
+	if softAt, ok := w.softDeadline(now); ok {
+		if wait := softAt.Sub(now); wait > 0 {
+			d.mu.Lock()
+			d.after(wait, func() { d.begin(ErrSoftTimeout) })
+			d.mu.Unlock()
+		} else {
+			d.begin(ErrSoftTimeout)
+		}
+	}
+	if nested {
+		ctx = drainKey.With(ctx, d)
+	}
+	return ctx
+}
+
+// begin starts draining, unless already draining or stopped.
+func (d *drainer) begin(reason error) bool {
+	d.mu.Lock()
+	if d.closed || d.isDrained() {
+		d.mu.Unlock()
+		return false
+	}
+	d.reason = reason
+	close(d.drained)
+	if d.armed {
+		d.armHardTimeout()
+	}
+	d.mu.Unlock()
+	// outside d.mu: tick reads the reason under statusChange.L
+	if d.w != nil {
+		d.w.signalStatusChange()
+	}
+	return true
+}
+
+// armHardTimeout cancels the Steps' context after Option.HardTimeout. d.mu
+// must be held.
+func (d *drainer) armHardTimeout() {
+	switch cancel := d.cancel; {
+	case cancel == nil:
+	case *d.w.Option.HardTimeout <= 0:
+		cancel(ErrHardTimeout)
+	default:
+		d.after(*d.w.Option.HardTimeout, func() { cancel(ErrHardTimeout) })
+	}
+}
+
+// after runs fn after wait on the Workflow's clock, unless stopped before.
+// d.mu must be held.
+func (d *drainer) after(wait time.Duration, fn func()) {
+	if !d.closed {
+		d.timers = append(d.timers, d.w.clock().AfterFunc(wait, fn))
+	}
+}
+
+// stop disarms the timers and releases the Steps' context.
+func (d *drainer) stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed = true
+	close(d.stopped)
+	for _, t := range d.timers {
+		t.Stop()
+	}
+	if d.cancel != nil {
+		d.cancel(nil)
+	}
+}
+
+func (d *drainer) isDrained() bool {
+	select {
+	case <-d.drained:
+		return true
+	default:
+		return false
+	}
+}
+
+func (d *drainer) drainReason() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.reason
+}
+
+// drainedBy returns why the Workflow stopped starting new Steps, or nil
+// while it still starts them.
+func (w *Workflow) drainedBy() error {
+	if d := w.drain.Load(); d != nil && d.isDrained() {
+		return d.drainReason()
+	}
+	return nil
+}
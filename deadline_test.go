@@ -0,0 +1,119 @@
+package flow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflowDeadline(t *testing.T) {
+	t.Parallel()
+	duration := func(d time.Duration) *time.Duration { return &d }
+	t.Run("soft timeout stops starting steps, running ones finish", func(t *testing.T) {
+		clk := clock.NewMock()
+		started, release := make(chan struct{}), make(chan struct{})
+		a := flow.Func("a", func(ctx context.Context) error {
+			close(started)
+			<-release
+			return ctx.Err()
+		})
+		b := flow.NoOp("b")
+		w := new(flow.Workflow).Add(flow.Step(b).DependsOn(a))
+		w.Option.Clock = clk
+		w.Option.SoftTimeout = duration(time.Minute)
+
+		done := make(chan error)
+		go func() { done <- w.Do(context.Background()) }()
+		<-started
+		clk.Add(time.Minute)
+		require.Eventually(t, func() bool { return w.StateOf(b).GetStatus() == flow.Canceled }, time.Second, time.Millisecond)
+		close(release)
+
+		err := <-done
+		var errWorkflow flow.ErrWorkflow
+		require.ErrorAs(t, err, &errWorkflow)
+		assert.Equal(t, flow.Succeeded, errWorkflow[a].Status)
+		assert.Equal(t, flow.Canceled, errWorkflow[b].Status)
+		assert.ErrorIs(t, errWorkflow[b].Err, flow.ErrSoftTimeout)
+	})
+	t.Run("hard timeout cancels running steps after the grace period", func(t *testing.T) {
+		clk := clock.NewMock()
+		start := clk.Now()
+		var canceledAt time.Time
+		var cause error
+		a := flow.Func("a", func(ctx context.Context) error {
+			<-ctx.Done()
+			canceledAt, cause = clk.Now(), context.Cause(ctx)
+			return ctx.Err()
+		})
+		w := new(flow.Workflow).Add(flow.Step(a))
+		w.Option.Clock = clk
+		w.Option.SoftTimeout = duration(time.Minute)
+		w.Option.HardTimeout = duration(30 * time.Second)
+
+		done := make(chan error)
+		go func() { done <- w.Do(context.Background()) }()
+		for {
+			select {
+			case err := <-done:
+				require.Error(t, err)
+				assert.Equal(t, flow.Canceled, w.StateOf(a).GetStatus())
+				assert.ErrorIs(t, cause, flow.ErrHardTimeout)
+				assert.GreaterOrEqual(t, canceledAt.Sub(start), 90*time.Second)
+				return
+			default:
+				clk.Add(10 * time.Second)
+			}
+		}
+	})
+	t.Run("deadline already passed", func(t *testing.T) {
+		clk := clock.NewMock()
+		deadline := clk.Now()
+		a, b := flow.NoOp("a"), flow.NoOp("b")
+		w := new(flow.Workflow).Add(flow.Step(b).DependsOn(a))
+		w.Option.Clock = clk
+		w.Option.Deadline = &deadline
+		var errWorkflow flow.ErrWorkflow
+		require.ErrorAs(t, w.Do(context.Background()), &errWorkflow)
+		for _, step := range []flow.Steper{a, b} {
+			assert.Equal(t, flow.Canceled, errWorkflow[step].Status)
+			assert.ErrorIs(t, errWorkflow[step].Err, flow.ErrSoftTimeout)
+		}
+	})
+	t.Run("sub-workflows drain with their parent", func(t *testing.T) {
+		clk := clock.NewMock()
+		started, release := make(chan struct{}), make(chan struct{})
+		a := flow.Func("a", func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+		b := flow.NoOp("b")
+		inner := new(flow.Workflow).Add(flow.Step(b).DependsOn(a))
+		w := new(flow.Workflow).Add(flow.Step(inner))
+		w.Option.Clock = clk
+		w.Option.SoftTimeout = duration(time.Minute)
+
+		done := make(chan error)
+		go func() { done <- w.Do(context.Background()) }()
+		<-started
+		clk.Add(time.Minute)
+		require.Eventually(t, func() bool { return inner.StateOf(b).GetStatus() == flow.Canceled }, time.Second, time.Millisecond)
+		close(release)
+		require.Error(t, <-done)
+		assert.Equal(t, flow.Succeeded, inner.StateOf(a).GetStatus())
+		assert.ErrorIs(t, inner.StateOf(b).GetError(), flow.ErrSoftTimeout)
+		assert.Nil(t, inner.Option.SoftTimeout, "not inherited")
+	})
+	t.Run("no deadline reached", func(t *testing.T) {
+		w := new(flow.Workflow).Add(flow.Step(flow.NoOp("a")))
+		w.Option.SoftTimeout = duration(time.Hour)
+		w.Option.HardTimeout = duration(time.Hour)
+		assert.NoError(t, w.Do(context.Background()))
+	})
+}
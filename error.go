@@ -1,7 +1,11 @@
 package flow
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"reflect"
 	"runtime"
 	"sort"
 	"strings"
@@ -111,9 +115,9 @@ func (e ErrWithStackTraces) StackTraces() []string {
 //   - anything else                                    → Failed
 //
 // Note: context.Canceled / context.DeadlineExceeded are NOT translated here —
-// the worker in workflow.go applies that policy after consulting
-// DefaultIsCanceled, so the per-step Status ends up Canceled for cancellation
-// errors even if StatusFromError reported Failed.
+// StatusOf applies that policy after consulting DefaultIsCanceled, so the
+// per-step Status ends up Canceled for cancellation errors even if
+// StatusFromError reported Failed.
 func StatusFromError(err error) StepStatus {
 	if err == nil {
 		return Succeeded
@@ -134,13 +138,42 @@ func StatusFromError(err error) StepStatus {
 	}
 }
 
+// StatusOf returns the terminal StepStatus a Workflow records for a Step
+// whose Do (with its interceptors and retries) returned err: that of
+// StatusFromError, except that cancellation errors (context.Canceled,
+// context.DeadlineExceeded, or recognised by DefaultIsCanceled) are Canceled
+// rather than Failed. Use it to classify errors the way the Workflow does,
+// e.g. in an interceptor.
+func StatusOf(err error) StepStatus {
+	status := StatusFromError(err)
+	if status == Failed {
+		switch {
+		case DefaultIsCanceled(err),
+			errors.Is(err, context.Canceled),
+			errors.Is(err, context.DeadlineExceeded):
+			status = Canceled
+		}
+	}
+	return status
+}
+
 // StepResult is the public terminal record of a single step's run: its final
-// status, the last error observed (may be nil for Succeeded), and the wall
-// clock time the step finished. FinishedAt is zero if the step never ran.
+// status, the last error observed (may be nil for Succeeded), and when the
+// step became ready, started and finished, as read from Option.Clock.
+// FinishedAt is zero if the step never ran.
+//
+// ReadyAt and StartedAt are only set for steps that were dispatched to run:
+// steps settled inline by their Condition (Skipped / Canceled) have them
+// zero. StartedAt - ReadyAt is the time spent waiting for a concurrency
+// lease (Option.MaxConcurrency); FinishedAt - StartedAt - Running is the time
+// spent backing off between retry attempts.
 type StepResult struct {
 	Status     StepStatus
 	Err        error
-	FinishedAt time.Time
+	ReadyAt    time.Time     // when every upstream had terminated and the Condition let the step run.
+	StartedAt  time.Time     // when the step got its lease and its worker started.
+	FinishedAt time.Time     // when the step reached its terminal status.
+	Running    time.Duration // total time spent inside attempts (interceptors, callbacks and Do).
 }
 
 // Error renders a StepResult as:
@@ -251,6 +284,80 @@ func (e ErrWorkflow) AllSucceededOrSkipped() bool {
 // single-runner: wait for the in-flight Do to return before invoking again.
 var ErrWorkflowIsRunning = fmt.Errorf("Workflow is running, please wait for it terminated")
 
+// ErrFailFast is the cancellation cause installed on a Workflow's context
+// when a Step fails while WorkflowOption.FailFast is set (and the Step hasn't
+// opted out via AddSteps.DontFailFast). Step is the root step whose failure triggered the
+// cancellation and Err is the error it failed with.
+//
+// Pending Steps that are settled Canceled because of it carry the
+// ErrFailFast as their StepResult.Err; read it from a running Step with
+// context.Cause(ctx).
+type ErrFailFast struct {
+	Step Steper
+	Err  error
+}
+
+func (e ErrFailFast) Error() string {
+	return fmt.Sprintf("fail fast: %s failed", String(e.Step))
+}
+
+// ErrNoOutput is returned by Output when the Workflow has no Step with an
+// output of type Type at Step.
+type ErrNoOutput struct {
+	Step Steper
+	Type reflect.Type
+}
+
+func (e ErrNoOutput) Error() string {
+	return fmt.Sprintf("no output of type %s for step %s in the workflow", e.Type, String(e.Step))
+}
+
+// ErrInterrupted is the StepResult.Err of the Steps not started because
+// RunWithSignals received Signal, and the cancellation cause of the
+// Workflow's context on a second signal.
+type ErrInterrupted struct {
+	Signal os.Signal
+}
+
+func (e ErrInterrupted) Error() string {
+	return fmt.Sprintf("interrupted by %s", e.Signal)
+}
+
+// ErrLoopExhausted is returned by a LoopStep whose Until check didn't hold
+// within MaxIterations iterations.
+type ErrLoopExhausted struct {
+	Iterations int
+}
+
+func (e ErrLoopExhausted) Error() string {
+	return fmt.Sprintf("loop condition not met after %d iteration(s)", e.Iterations)
+}
+
+// ErrQuorum is returned by a ParallelStep (Race / Quorum) when fewer than
+// Want children succeeded. Results holds every child's outcome; it is not
+// exposed through Unwrap, so that children canceled on the way don't make
+// the ParallelStep itself look Canceled.
+type ErrQuorum struct {
+	Want, Succeeded int
+	Results         ErrWorkflow
+}
+
+func (e ErrQuorum) Error() string {
+	rv := fmt.Sprintf("quorum not reached: %d step(s) succeeded, want %d", e.Succeeded, e.Want)
+	if len(e.Results) > 0 {
+		rv += "\n\t" + indent(strings.TrimSuffix(e.Results.Error(), "\n"))
+	}
+	return rv
+}
+
+// ErrValidation is returned by Diagnostics.Err: the SeverityError
+// Diagnostics found by Workflow.Validate.
+type ErrValidation Diagnostics
+
+func (e ErrValidation) Error() string {
+	return fmt.Sprintf("Validation Error:\n\t%s", indent(Diagnostics(e).String()))
+}
+
 // ErrCycleDependency is returned by Workflow.Do's preflight check when the
 // declared graph isn't acyclic. It maps each step still in a cycle to the
 // upstream step(s) that prevented it from being topologically scanned.
@@ -2,6 +2,7 @@ package flow_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -125,3 +126,19 @@ func TestErrWorkflowTieBreakByName(t *testing.T) {
 	require.GreaterOrEqual(t, posZ, 0, "Z-step not found in error output")
 	assert.Less(t, posA, posZ, "A-step should appear before Z-step (tie-break by name)")
 }
+
+func TestStatusOf(t *testing.T) {
+	t.Parallel()
+	boom := errors.New("boom")
+	for err, want := range map[error]flow.StepStatus{
+		nil:                flow.Succeeded,
+		boom:               flow.Failed,
+		flow.Skip(boom):    flow.Skipped,
+		flow.Cancel(boom):  flow.Canceled,
+		flow.Succeed(boom): flow.Succeeded,
+		context.Canceled:   flow.Canceled,
+		fmt.Errorf("%w", context.DeadlineExceeded): flow.Canceled,
+	} {
+		assert.Equal(t, want, flow.StatusOf(err), "%v", err)
+	}
+}
@@ -230,3 +230,35 @@ func TestStepResultFinishedAtPopulated(t *testing.T) {
 	result := w.StateOf(step).GetStepResult()
 	assert.False(t, result.FinishedAt.IsZero(), "FinishedAt should be set after step execution")
 }
+
+func TestStepResultTimings(t *testing.T) {
+	mockClock := clock.NewMock()
+	start := mockClock.Now()
+	maxConcurrency := 1
+	first := Func("first", func(ctx context.Context) error {
+		mockClock.Add(time.Second)
+		return nil
+	})
+	second := Func("second", func(ctx context.Context) error {
+		mockClock.Add(2 * time.Second)
+		return nil
+	})
+	skipped := Func("skipped", func(ctx context.Context) error { return nil })
+	w := &Workflow{Option: WorkflowOption{Clock: mockClock, MaxConcurrency: &maxConcurrency}}
+	w.Add(
+		Pipe(first, second),
+		Steps(skipped).When(AnyFailed),
+	)
+	assert.NoError(t, w.Do(context.Background()))
+
+	result := w.StateOf(second).GetStepResult()
+	assert.Equal(t, start.Add(time.Second), result.ReadyAt)
+	assert.Equal(t, start.Add(time.Second), result.StartedAt)
+	assert.Equal(t, start.Add(3*time.Second), result.FinishedAt)
+	assert.Equal(t, 2*time.Second, result.Running)
+
+	result = w.StateOf(skipped).GetStepResult()
+	assert.True(t, result.ReadyAt.IsZero(), "inline-settled steps are never ready")
+	assert.True(t, result.StartedAt.IsZero(), "inline-settled steps never start")
+	assert.False(t, result.FinishedAt.IsZero())
+}
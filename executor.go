@@ -0,0 +1,41 @@
+package flow
+
+import "context"
+
+// Executor runs Steps' Do on behalf of a Workflow, set in
+// WorkflowOption.Executor. The scheduler, Conditions, retries, timeouts,
+// interceptors and BeforeStep / AfterStep callbacks stay with the Workflow;
+// only each attempt's Do call goes through Execute, so an Executor may run
+// it elsewhere (another process, a remote worker; see package flowremote)
+// and report the outcome as the returned error.
+//
+// Execute receives the root Step as added to the Workflow, wrappers (Name,
+// Mock, …) included: an Executor looking for the Step types it can run
+// remotely should walk the Unwrap chain, as package flowremote does.
+//
+// Sub-workflows (Steps with a WorkflowOptionReceiver in their Unwrap chain)
+// are always run in-process: their own Steps are dispatched through the
+// Executor they inherit.
+type Executor interface {
+	Execute(ctx context.Context, step Steper) error
+}
+
+// ExecutorFunc adapts a function to an Executor.
+type ExecutorFunc func(context.Context, Steper) error
+
+func (f ExecutorFunc) Execute(ctx context.Context, step Steper) error { return f(ctx, step) }
+
+// InProcess is the default Executor: it calls step.Do in the calling
+// goroutine.
+var InProcess Executor = ExecutorFunc(func(ctx context.Context, step Steper) error {
+	return step.Do(ctx)
+})
+
+// executorFor returns the Executor running step: Option.Executor, unless
+// step is a sub-workflow.
+func (w *Workflow) executorFor(step Steper) Executor {
+	if w.Option.Executor == nil || findOptionReceiver(step) != nil {
+		return InProcess
+	}
+	return w.Option.Executor
+}
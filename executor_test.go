@@ -0,0 +1,50 @@
+package flow_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutor(t *testing.T) {
+	t.Parallel()
+	var (
+		mu       sync.Mutex
+		executed []string
+		events   []string
+	)
+	record := func(events *[]string, e string) {
+		mu.Lock()
+		defer mu.Unlock()
+		*events = append(*events, e)
+	}
+	a := flow.Func("a", func(ctx context.Context) error {
+		record(&events, "a.Do")
+		return nil
+	})
+	b := flow.Func("b", func(ctx context.Context) error { return nil })
+	inner := new(flow.Workflow).Add(flow.Step(b))
+	w := new(flow.Workflow).Add(
+		flow.Step(a).
+			BeforeStep(func(ctx context.Context, _ flow.Steper) (context.Context, error) {
+				record(&events, "a.Before")
+				return ctx, nil
+			}).
+			AfterStep(func(ctx context.Context, _ flow.Steper, err error) error {
+				record(&events, "a.After")
+				return err
+			}),
+		flow.Step(inner).DependsOn(a),
+	)
+	w.Option.Executor = flow.ExecutorFunc(func(ctx context.Context, step flow.Steper) error {
+		record(&executed, flow.String(step))
+		return flow.InProcess.Execute(ctx, step)
+	})
+	require.NoError(t, w.Do(context.Background()))
+	assert.Equal(t, []string{"a", "b"}, executed, "sub-workflows run in-process, their steps through the inherited Executor")
+	assert.Equal(t, []string{"a.Before", "a.Do", "a.After"}, events, "callbacks stay with the Workflow")
+}
@@ -0,0 +1,148 @@
+package flow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Versioner is implemented by Steps that version their behaviour. Bump the
+// Version when a Step changes in a way runs of the previous definition are
+// not compatible with (e.g. its Output changed meaning): the Fingerprint of
+// every Workflow containing it changes too.
+type Versioner interface {
+	Version() string
+}
+
+// Fingerprint returns a stable digest of the definition of w, to log with
+// each run, or to tell whether a persisted run (see package flowrecord) or a
+// cache entry comes from the same definition. It covers:
+//
+//   - each Step's identity: its type, its String method if any, its Version
+//     if it is a Versioner, and the identities of the Steps it wraps; a
+//     sub-workflow is identified by its own Fingerprint. The String method
+//     of a wrapper is only used for a NamedStep or StringerNamedStep: others,
+//     like MockStep, render what they wrap, or fall back to String's
+//     "<Type>(<addr>)" form;
+//   - the dependencies between the root Steps;
+//   - each root Step's Condition, by function name, and its Timeout, Retry
+//     (Attempts and TimeoutPerTry), DontFailFast and IdempotencyKey options.
+//
+// Leaves without a String method are identified by type alone; pointer
+// addresses never take part, so building the same definition twice, even in
+// different processes, gives the same Fingerprint, as long as the String
+// methods of leaves don't print addresses either. The order of Add calls
+// doesn't matter.
+func (w *Workflow) Fingerprint() string {
+	h := sha256.New()
+	w.writeDefinition(h)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeDefinition writes the canonical text Fingerprint digests: a paragraph
+// per root step, sorted.
+func (w *Workflow) writeDefinition(out io.Writer) {
+	p := w.topology()
+	ids := make(map[Steper]string, len(p.order))
+	for _, root := range p.order {
+		ids[root] = identity(root)
+	}
+	paragraphs := make([]string, 0, len(p.order))
+	for _, root := range p.order {
+		var b strings.Builder
+		fmt.Fprintf(&b, "step %s\n", ids[root])
+		ups := make([]string, 0, len(p.ups[root]))
+		for _, up := range p.ups[root] {
+			ups = append(ups, ids[up])
+		}
+		slices.Sort(ups)
+		for _, up := range ups {
+			fmt.Fprintf(&b, "\tup %s\n", up)
+		}
+		option := w.steps[root].Option()
+		if option.Condition != nil {
+			fmt.Fprintf(&b, "\twhen %s\n", funcName(option.Condition))
+		}
+		if option.Timeout != nil {
+			fmt.Fprintf(&b, "\ttimeout %s\n", *option.Timeout)
+		}
+		if r := option.RetryOption; r != nil {
+			fmt.Fprintf(&b, "\tretry attempts=%d timeoutPerTry=%s\n", r.Attempts, r.TimeoutPerTry)
+		}
+		if option.DontFailFast {
+			fmt.Fprintf(&b, "\tdontFailFast\n")
+		}
+		if option.IdempotencyKey != nil {
+			fmt.Fprintf(&b, "\tidempotencyKey %s\n", funcName(option.IdempotencyKey))
+		}
+		paragraphs = append(paragraphs, b.String())
+	}
+	slices.Sort(paragraphs)
+	for _, paragraph := range paragraphs {
+		io.WriteString(out, paragraph)
+	}
+}
+
+// identity renders step for writeDefinition, see Fingerprint.
+func identity(step Steper) string {
+	var b strings.Builder
+	b.WriteString(reflect.TypeOf(step).String())
+	if s, ok := step.(fmt.Stringer); ok && (!isWrapper(step) || isNamed(step)) {
+		fmt.Fprintf(&b, "(%q)", s.String())
+	}
+	if v, ok := step.(Versioner); ok {
+		fmt.Fprintf(&b, "@%q", v.Version())
+	}
+	if sub, ok := step.(interface{ Fingerprint() string }); ok {
+		fmt.Fprintf(&b, "#%s", sub.Fingerprint())
+		return b.String()
+	}
+	var children []Steper
+	switch u := step.(type) {
+	case interface{ Unwrap() Steper }:
+		children = []Steper{u.Unwrap()}
+	case interface{ Unwrap() []Steper }:
+		children = u.Unwrap()
+	}
+	ids := make([]string, 0, len(children))
+	for _, child := range children {
+		if child != nil {
+			ids = append(ids, identity(child))
+		}
+	}
+	if len(ids) > 0 {
+		fmt.Fprintf(&b, "{%s}", strings.Join(ids, ", "))
+	}
+	return b.String()
+}
+
+// isWrapper reports whether step wraps other Steps.
+func isWrapper(step Steper) bool {
+	switch step.(type) {
+	case interface{ Unwrap() Steper }, interface{ Unwrap() []Steper }:
+		return true
+	}
+	return false
+}
+
+// isNamed reports whether step is a wrapper naming the Step it wraps.
+func isNamed(step Steper) bool {
+	switch step.(type) {
+	case *NamedStep, *StringerNamedStep:
+		return true
+	}
+	return false
+}
+
+// funcName returns the name of the function fn, e.g. "flow.AllSucceeded".
+func funcName(fn any) string {
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return ""
+}
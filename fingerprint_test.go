@@ -0,0 +1,88 @@
+package flow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type versioned struct {
+	NoOpStep
+	version string
+}
+
+func (v *versioned) Version() string { return v.version }
+
+// unnamed is a leaf Step without a String method.
+type unnamed struct{ n int }
+
+func (*unnamed) Do(context.Context) error { return nil }
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+	type def struct {
+		names    [3]string
+		when     Condition
+		attempts uint64
+		timeout  time.Duration
+		version  string
+	}
+	base := def{names: [3]string{"a", "b", "c"}, when: Always, attempts: 3, timeout: time.Minute, version: "1"}
+	build := func(d def, reversed bool) *Workflow {
+		a, b, c := NoOp(d.names[0]), NoOp(d.names[1]), &versioned{NoOpStep{Name: d.names[2]}, d.version}
+		builders := []Builder{
+			Step(b).DependsOn(a).When(d.when).Retry(func(ro *RetryOption) { ro.Attempts = d.attempts }),
+			Step(c).DependsOn(b).Timeout(d.timeout),
+		}
+		if reversed {
+			builders[0], builders[1] = builders[1], builders[0]
+		}
+		return new(Workflow).Add(builders...)
+	}
+	fp := build(base, false).Fingerprint()
+	assert.Len(t, fp, 64)
+
+	t.Run("stable", func(t *testing.T) {
+		assert.Equal(t, fp, build(base, false).Fingerprint(), "new instances")
+		assert.Equal(t, fp, build(base, true).Fingerprint(), "Add order")
+		w := build(base, false)
+		assert.NoError(t, w.Do(context.Background()))
+		assert.Equal(t, fp, w.Fingerprint(), "runs")
+	})
+	t.Run("changes with the definition", func(t *testing.T) {
+		for name, d := range map[string]def{
+			"name":      {names: [3]string{"a", "B", "c"}, when: base.when, attempts: base.attempts, timeout: base.timeout, version: base.version},
+			"condition": {names: base.names, when: AnySucceeded, attempts: base.attempts, timeout: base.timeout, version: base.version},
+			"retry":     {names: base.names, when: base.when, attempts: 5, timeout: base.timeout, version: base.version},
+			"timeout":   {names: base.names, when: base.when, attempts: base.attempts, timeout: time.Hour, version: base.version},
+			"version":   {names: base.names, when: base.when, attempts: base.attempts, timeout: base.timeout, version: "2"},
+		} {
+			assert.NotEqual(t, fp, build(d, false).Fingerprint(), name)
+		}
+		w := build(base, false)
+		steps := map[string]Steper{}
+		for _, step := range w.order {
+			steps[String(step)] = step
+		}
+		w.Add(Step(steps["c"]).DependsOn(steps["a"]))
+		assert.NotEqual(t, fp, w.Fingerprint(), "edge")
+	})
+	t.Run("composite and nested steps", func(t *testing.T) {
+		nested := func(inner string) string {
+			sub := new(Workflow).Add(Step(NoOp(inner)))
+			return new(Workflow).Add(Name(sub, "sub")).Fingerprint()
+		}
+		assert.Equal(t, nested("x"), nested("x"))
+		assert.NotEqual(t, nested("x"), nested("y"))
+		wrapped := func(inner string) string {
+			return new(Workflow).Add(Name(NoOp(inner), "named")).Fingerprint()
+		}
+		assert.NotEqual(t, wrapped("x"), wrapped("y"))
+		mocked := func() string {
+			return new(Workflow).Add(Mock(&unnamed{}, func(context.Context) error { return nil })).Fingerprint()
+		}
+		assert.Equal(t, mocked(), mocked(), "no address from the mock's String")
+	})
+}
@@ -0,0 +1,190 @@
+// Package flowrecord records the outcome of every Step in a Workflow run to
+// a file, and replays a recording by substituting each recorded Step's Do
+// with its captured result. Use it to reproduce a failed production run
+// locally without calling real services:
+//
+//	// In production:
+//	rec := flowrecord.NewRecorder()
+//	rec.Install(w)
+//	err := w.Do(ctx)
+//	_ = rec.Save("run.json")
+//
+//	// Locally, on a Workflow built by the same code:
+//	recording, err := flowrecord.Load("run.json")
+//	flowrecord.Replay(w, recording)
+//	err = w.Do(ctx) // same statuses, same outputs, no real calls
+//
+// Steps are keyed by flow.String(step), so recorded Steps need stable names
+// (Func / FuncIO, flow.Name, or a String method); Steps rendered with a
+// pointer address cannot be matched across processes.
+//
+// Besides the status and error, the recorder captures the state of the
+// first Recordable layer in each Step's Unwrap chain and the replay
+// restores it before returning the recorded error. *flow.Function is
+// Recordable (its Input and Output are serialized), so data flowing through
+// Input / Output callbacks is reproduced; other Step types opt in by
+// implementing Recordable.
+package flowrecord
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	flow "github.com/Azure/go-workflow"
+)
+
+// Recordable is implemented by Steps that expose serializable state. The
+// recorder stores MarshalJSON's result after the Step ran; replay feeds it
+// back through UnmarshalJSON instead of running Do. It is
+// flow.Serializable, found with flow.SerializableOf.
+type Recordable = flow.Serializable
+
+// Recording is the serialized outcome of a run, keyed by flow.String(step).
+type Recording struct {
+	// Fingerprint is the flow.Workflow.Fingerprint of the recorded Workflow.
+	// Compare it with the one of the Workflow to replay on, before Replay,
+	// to detect a changed definition.
+	Fingerprint string           `json:"fingerprint,omitempty"`
+	Steps       map[string]Entry `json:"steps"`
+}
+
+// Entry is the recorded outcome of one Step.
+type Entry struct {
+	Status flow.StepStatus `json:"status"`
+	// Error is the message of the error the Step returned, if any.
+	Error string `json:"error,omitempty"`
+	// State is the Step's Recordable state, if it has one.
+	State json.RawMessage `json:"state,omitempty"`
+	// StateError explains why State is missing, if MarshalJSON failed.
+	StateError string `json:"stateError,omitempty"`
+}
+
+// Recorder is a flow.StepInterceptor capturing an Entry for every Step it
+// wraps. Sub-workflows inherit it through Option.StepInterceptors, so their
+// Steps are recorded too; the sub-workflow Steps themselves are not, their
+// outcome being the aggregate of their inner Steps.
+type Recorder struct {
+	mu        sync.Mutex
+	recording Recording
+	workflow  *flow.Workflow // set by Install.
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{recording: Recording{Steps: make(map[string]Entry)}}
+}
+
+// Install appends r to w.Option.StepInterceptors, and keeps w to take its
+// Fingerprint in Recording, so Steps added after Install are covered.
+func (r *Recorder) Install(w *flow.Workflow) {
+	w.Option.StepInterceptors = append(w.Option.StepInterceptors, r)
+	r.mu.Lock()
+	r.workflow = w
+	r.mu.Unlock()
+}
+
+// InterceptStep implements flow.StepInterceptor.
+func (r *Recorder) InterceptStep(ctx context.Context, step flow.Steper, next func(context.Context) error) error {
+	err := next(ctx)
+	if workflowOf(step) != nil {
+		return err
+	}
+	entry := Entry{Status: flow.StatusOf(err)}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if rec := flow.SerializableOf(step); rec != nil {
+		if state, mErr := rec.MarshalJSON(); mErr != nil {
+			entry.StateError = mErr.Error()
+		} else {
+			entry.State = state
+		}
+	}
+	r.mu.Lock()
+	r.recording.Steps[flow.String(step)] = entry
+	r.mu.Unlock()
+	return err
+}
+
+// Recording returns a copy of what has been recorded so far.
+func (r *Recorder) Recording() *Recording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rv := &Recording{Steps: make(map[string]Entry, len(r.recording.Steps))}
+	if r.workflow != nil {
+		rv.Fingerprint = r.workflow.Fingerprint()
+	}
+	for name, entry := range r.recording.Steps {
+		rv.Steps[name] = entry
+	}
+	return rv
+}
+
+// WriteTo writes the recording as indented JSON.
+func (r *Recorder) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.MarshalIndent(r.Recording(), "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// Save writes the recording to the file at path, replacing it.
+func (r *Recorder) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := r.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read decodes a Recording written by Recorder.WriteTo.
+func Read(r io.Reader) (*Recording, error) {
+	rec := new(Recording)
+	if err := json.NewDecoder(r).Decode(rec); err != nil {
+		return nil, err
+	}
+	if rec.Steps == nil {
+		rec.Steps = make(map[string]Entry)
+	}
+	return rec, nil
+}
+
+// Load reads a Recording from the file at path.
+func Load(path string) (*Recording, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// workflowLike is satisfied by *flow.Workflow and by any type embedding it.
+type workflowLike interface {
+	Steps() []flow.Steper
+	Add(...flow.Builder) *flow.Workflow
+	StateOf(flow.Steper) *flow.State
+}
+
+// workflowOf returns the first nested workflow in step's Unwrap chain (step
+// itself included), or nil.
+func workflowOf(step flow.Steper) workflowLike {
+	var found workflowLike
+	flow.Traverse(step, func(s flow.Steper, _ []flow.Steper) flow.TraverseDecision {
+		if w, ok := s.(workflowLike); ok {
+			found = w
+			return flow.TraverseStop
+		}
+		return flow.TraverseContinue
+	})
+	return found
+}
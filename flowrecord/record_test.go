@@ -0,0 +1,155 @@
+package flowrecord_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/Azure/go-workflow/flowrecord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// vmStep is a non-Function Step that opts into recording.
+type vmStep struct {
+	ID string
+}
+
+func (v *vmStep) String() string { return "vm" }
+func (v *vmStep) Do(context.Context) error {
+	v.ID = "vm-42"
+	return nil
+}
+func (v *vmStep) MarshalJSON() ([]byte, error) { return json.Marshal(v.ID) }
+func (v *vmStep) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &v.ID)
+}
+
+// pipeline builds the same graph in "production" (live=true) and in the
+// replay (live=false, where every Do fails if it is actually called).
+type pipeline struct {
+	w       *flow.Workflow
+	fetch   *flow.Function[struct{}, string]
+	vm      *vmStep
+	report  *flow.Function[string, string]
+	publish *flow.Function[struct{}, struct{}]
+	inner   *flow.Function[struct{}, int]
+}
+
+var errNotReplayed = errors.New("real call during replay")
+
+func newPipeline(live bool) *pipeline {
+	p := &pipeline{vm: &vmStep{}}
+	p.fetch = flow.FuncO("fetch", func(ctx context.Context) (string, error) {
+		if !live {
+			return "", errNotReplayed
+		}
+		return "payload", nil
+	})
+	p.report = flow.FuncIO("report", func(ctx context.Context, in string) (string, error) {
+		if !live {
+			return "", errNotReplayed
+		}
+		return "report of " + in, nil
+	})
+	p.publish = flow.Func("publish", func(ctx context.Context) error {
+		if !live {
+			return errNotReplayed
+		}
+		return errors.New("registry unavailable")
+	})
+	p.inner = flow.FuncO("inner", func(ctx context.Context) (int, error) {
+		if !live {
+			return 0, errNotReplayed
+		}
+		return 7, nil
+	})
+	sub := new(flow.Workflow)
+	sub.Add(flow.Step(p.inner))
+	p.w = new(flow.Workflow)
+	if !live {
+		p.vm.Do(context.Background()) // make sure the replay restores, not re-runs
+		p.vm.ID = "stale"
+	}
+	p.w.Add(
+		flow.Steps(p.fetch, p.vm, sub),
+		flow.Step(p.report).DependsOn(p.fetch).Input(func(ctx context.Context, r *flow.Function[string, string]) error {
+			r.Input = p.fetch.Output
+			return nil
+		}),
+		flow.Steps(p.publish).DependsOn(p.report),
+	)
+	return p
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	t.Parallel()
+	live := newPipeline(true)
+	rec := flowrecord.NewRecorder()
+	rec.Install(live.w)
+	liveErr := live.w.Do(context.Background())
+	require.Error(t, liveErr)
+
+	path := filepath.Join(t.TempDir(), "run.json")
+	require.NoError(t, rec.Save(path))
+	recording, err := flowrecord.Load(path)
+	require.NoError(t, err)
+	assert.Len(t, recording.Steps, 5)
+	assert.Equal(t, flow.Failed, recording.Steps["publish"].Status)
+	assert.Equal(t, "registry unavailable", recording.Steps["publish"].Error)
+
+	replayed := newPipeline(false)
+	assert.NotEmpty(t, recording.Fingerprint)
+	assert.Equal(t, replayed.w.Fingerprint(), recording.Fingerprint, "same definition")
+	assert.Equal(t, 5, flowrecord.Replay(replayed.w, recording))
+	replayErr := replayed.w.Do(context.Background())
+	require.Error(t, replayErr)
+	assert.NotErrorIs(t, replayErr, errNotReplayed)
+	assert.ErrorContains(t, replayErr, "registry unavailable")
+
+	assert.Equal(t, "payload", replayed.fetch.Output)
+	assert.Equal(t, "report of payload", replayed.report.Output)
+	assert.Equal(t, "vm-42", replayed.vm.ID)
+	assert.Equal(t, 7, replayed.inner.Output)
+	assert.Equal(t, flow.Failed, replayed.w.StateOf(replayed.publish).GetStatus())
+	assert.Equal(t, flow.Succeeded, replayed.w.StateOf(replayed.inner).GetStatus())
+}
+
+func TestReplayStatuses(t *testing.T) {
+	t.Parallel()
+	recording := &flowrecord.Recording{Steps: map[string]flowrecord.Entry{
+		"skip":    {Status: flow.Skipped},
+		"cancel":  {Status: flow.Canceled, Error: "context canceled"},
+		"succeed": {Status: flow.Succeeded, Error: "with a note"},
+	}}
+	w := new(flow.Workflow)
+	var steps []flow.Steper
+	for _, name := range []string{"skip", "cancel", "succeed", "unrecorded"} {
+		step := flow.Func(name, func(ctx context.Context) error { return nil })
+		steps = append(steps, step)
+		w.Add(flow.Step(step))
+	}
+	assert.Equal(t, 3, flowrecord.Replay(w, recording))
+	_ = w.Do(context.Background())
+	for i, want := range []flow.StepStatus{flow.Skipped, flow.Canceled, flow.Succeeded, flow.Succeeded} {
+		assert.Equal(t, want, w.StateOf(steps[i]).GetStatus(), flow.String(steps[i]))
+	}
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+	rec := flowrecord.NewRecorder()
+	var buf bytes.Buffer
+	_, err := rec.WriteTo(&buf)
+	require.NoError(t, err)
+	recording, err := flowrecord.Read(&buf)
+	require.NoError(t, err)
+	assert.Empty(t, recording.Steps)
+
+	_, err = flowrecord.Read(bytes.NewBufferString("not json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+package flowrecord
+
+import (
+	"context"
+	"errors"
+
+	flow "github.com/Azure/go-workflow"
+)
+
+// Replay substitutes, via flow.Mock, the Do of every Step in w found in rec
+// (matched by flow.String(step)) with one that restores the recorded state
+// into the Step's Recordable layer and returns the recorded outcome.
+// Sub-workflows are searched recursively. Steps absent from rec are left
+// untouched and run for real; Steps settled inline during the recorded run
+// (Skipped / Canceled by their Condition) are settled the same way again,
+// since their upstreams replay the same statuses. It returns how many Steps
+// were substituted.
+//
+// Call Replay after the Workflow is fully built and before Do.
+func Replay(w *flow.Workflow, rec *Recording) int {
+	return replay(w, rec)
+}
+
+func replay(w workflowLike, rec *Recording) int {
+	n := 0
+	for _, root := range w.Steps() {
+		if sub := workflowOf(root); sub != nil {
+			n += replay(sub, rec)
+			continue
+		}
+		entry, ok := rec.Steps[flow.String(root)]
+		if !ok {
+			continue
+		}
+		w.Add(flow.Mock(root, func(ctx context.Context) error {
+			if len(entry.State) > 0 {
+				if r := flow.SerializableOf(root); r != nil {
+					if err := r.UnmarshalJSON(entry.State); err != nil {
+						return err
+					}
+				}
+			}
+			return entry.err()
+		}))
+		n++
+	}
+	return n
+}
+
+// err rebuilds an error carrying the recorded message that the Workflow
+// classifies into the recorded status.
+func (e Entry) err() error {
+	var err error
+	if e.Error != "" {
+		err = errors.New(e.Error)
+	}
+	switch e.Status {
+	case flow.Succeeded:
+		if err != nil {
+			return flow.Succeed(err)
+		}
+		return nil
+	case flow.Skipped:
+		return flow.Skip(orDefault(err, "skipped"))
+	case flow.Canceled:
+		return flow.Cancel(orDefault(err, "canceled"))
+	default:
+		return orDefault(err, "failed")
+	}
+}
+
+func orDefault(err error, msg string) error {
+	if err == nil {
+		return errors.New(msg)
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+package flowremote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	flow "github.com/Azure/go-workflow"
+)
+
+// NewHandler returns an http.Handler running the Tasks POSTed to it as JSON
+// with w, and responding with their Result as JSON. The Task runs with the
+// request's context, canceled if the coordinator gives up.
+func NewHandler(w *Worker) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var task Task
+		if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(w.Execute(r.Context(), task))
+	})
+}
+
+// HTTPExecutor is a flow.Executor POSTing the Steps registered in Registry
+// to a worker served by NewHandler at URL.
+type HTTPExecutor struct {
+	URL      string
+	Registry *Registry
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+func (e *HTTPExecutor) Execute(ctx context.Context, step flow.Steper) error {
+	return dispatch(ctx, e.Registry, step, e.send)
+}
+
+func (e *HTTPExecutor) send(ctx context.Context, task Task) (Result, error) {
+	body, err := json.Marshal(task)
+	if err != nil {
+		return Result{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, bytes.NewReader(body))
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return Result{}, fmt.Errorf("flowremote: worker %s: %s: %s", e.URL, resp.Status, bytes.TrimSpace(msg))
+	}
+	var result Result
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return Result{}, fmt.Errorf("flowremote: worker %s: %w", e.URL, err)
+	}
+	return result, nil
+}
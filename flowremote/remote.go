@@ -0,0 +1,235 @@
+// Package flowremote dispatches Steps to workers in other processes or on
+// other machines, through a flow.Executor. The coordinator keeps the
+// scheduler, Conditions, retries, timeouts and callbacks; only Do runs on
+// the worker:
+//
+//	// Both sides register the Steps they can exchange, under the same names:
+//	reg := flowremote.NewRegistry()
+//	reg.Register("render", func() flow.Steper { return new(Render) })
+//
+//	// Worker:
+//	http.ListenAndServe(":8080", flowremote.NewHandler(flowremote.NewWorker(reg)))
+//
+//	// Coordinator:
+//	w.Option.Executor = &flowremote.HTTPExecutor{URL: "http://worker:8080", Registry: reg}
+//
+// A Step travels as a Task: its registered name and its JSON state
+// (encoding/json, so exported fields or a MarshalJSON / UnmarshalJSON pair,
+// e.g. *flow.Function's Input and Output). The worker builds a fresh Step
+// with the registered constructor, restores the state, runs Do, and sends
+// back a Result with the new state and the outcome, which the coordinator
+// applies to its own Step.
+//
+// Steps whose type isn't registered run in-process, so only the Steps worth
+// distributing need registering. A wrapped Step (flow.Name, …) is sent as
+// the first registered layer of its Unwrap chain, unless a flow.MockStep
+// comes first: a mock runs in-process.
+package flowremote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	flow "github.com/Azure/go-workflow"
+)
+
+// Registry maps names to Step constructors, so Steps can be sent by name.
+type Registry struct {
+	mu     sync.RWMutex
+	byName map[string]func() flow.Steper
+	byType map[reflect.Type][]string
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		byName: make(map[string]func() flow.Steper),
+		byType: make(map[reflect.Type][]string),
+	}
+}
+
+// Register makes the Steps built by newStep exchangeable under name. A Step
+// is sent under the name registered for its type; when several names share
+// a type (e.g. Functions), under the one equal to flow.String(step).
+// newStep must return a pointer, so the state can be restored into it.
+// Register panics if name is already registered.
+func (r *Registry) Register(name string, newStep func() flow.Steper) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byName[name]; ok {
+		panic(fmt.Sprintf("flowremote: step %q registered twice", name))
+	}
+	r.byName[name] = newStep
+	t := reflect.TypeOf(newStep())
+	r.byType[t] = append(r.byType[t], name)
+}
+
+// nameOf returns the name step is sent under, and whether it has one.
+func (r *Registry) nameOf(step flow.Steper) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := r.byType[reflect.TypeOf(step)]
+	if len(names) == 1 {
+		return names[0], true
+	}
+	for _, name := range names {
+		if name == flow.String(step) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func (r *Registry) newStep(name string) (flow.Steper, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	newStep, ok := r.byName[name]
+	if !ok {
+		return nil, false
+	}
+	return newStep(), true
+}
+
+// Task is a Step sent to a worker.
+type Task struct {
+	Step  string          `json:"step"`
+	State json.RawMessage `json:"state,omitempty"`
+	// Deadline is the coordinator's context deadline, if any.
+	Deadline *time.Time `json:"deadline,omitempty"`
+}
+
+// Result is the outcome of a Task.
+type Result struct {
+	State json.RawMessage `json:"state,omitempty"`
+	// Status is how the error Do returned is classified on the worker:
+	// Succeeded, Failed, Canceled or Skipped.
+	Status flow.StepStatus `json:"status"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// RemoteError is the error a Step returned on a worker.
+type RemoteError struct {
+	Step    string
+	Message string
+}
+
+func (e RemoteError) Error() string { return e.Message }
+
+// err rebuilds the error Do returned on the worker, keeping the status it
+// is classified as.
+func (r Result) err(step string) error {
+	if r.Status == flow.Succeeded && r.Error == "" {
+		return nil
+	}
+	err := RemoteError{Step: step, Message: r.Error}
+	switch r.Status {
+	case flow.Succeeded:
+		return flow.Succeed(err)
+	case flow.Canceled:
+		return flow.Cancel(err)
+	case flow.Skipped:
+		return flow.Skip(err)
+	default:
+		return err
+	}
+}
+
+// Worker runs Tasks with the Steps of a Registry.
+type Worker struct {
+	Registry *Registry
+}
+
+// NewWorker returns a Worker running the Steps registered in reg.
+func NewWorker(reg *Registry) *Worker { return &Worker{Registry: reg} }
+
+// Execute runs task and returns its Result; a panicking Do fails the Task.
+func (w *Worker) Execute(ctx context.Context, task Task) Result {
+	step, ok := w.Registry.newStep(task.Step)
+	if !ok {
+		return Result{Status: flow.Failed, Error: fmt.Sprintf("flowremote: step %q not registered", task.Step)}
+	}
+	if len(task.State) > 0 {
+		if err := json.Unmarshal(task.State, step); err != nil {
+			return Result{Status: flow.Failed, Error: fmt.Sprintf("flowremote: restore step %q: %s", task.Step, err)}
+		}
+	}
+	if task.Deadline != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, *task.Deadline)
+		defer cancel()
+	}
+	err := do(ctx, step)
+	result := Result{Status: flow.StatusOf(err)}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	state, mErr := json.Marshal(step)
+	if mErr != nil {
+		return Result{Status: flow.Failed, Error: fmt.Sprintf("flowremote: save step %q: %s", task.Step, mErr)}
+	}
+	result.State = state
+	return result
+}
+
+func do(ctx context.Context, step flow.Steper) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return step.Do(ctx)
+}
+
+// registered returns the first layer of step's single-child Unwrap chain
+// registered in reg, and its name. The walk stops at a flow.MockStep, whose
+// Do replaces that of the Step it wraps.
+func (r *Registry) registered(step flow.Steper) (flow.Steper, string, bool) {
+	for step != nil {
+		if name, ok := r.nameOf(step); ok {
+			return step, name, true
+		}
+		if _, isMock := step.(*flow.MockStep); isMock {
+			break
+		}
+		u, ok := step.(interface{ Unwrap() flow.Steper })
+		if !ok {
+			break
+		}
+		step = u.Unwrap()
+	}
+	return nil, "", false
+}
+
+// dispatch sends the registered layer of step as a Task with send and
+// applies the Result to it; steps without one run in-process.
+func dispatch(ctx context.Context, reg *Registry, root flow.Steper, send func(context.Context, Task) (Result, error)) error {
+	step, name, ok := reg.registered(root)
+	if !ok {
+		return root.Do(ctx)
+	}
+	state, err := json.Marshal(step)
+	if err != nil {
+		return fmt.Errorf("flowremote: save step %q: %w", name, err)
+	}
+	task := Task{Step: name, State: state}
+	if deadline, ok := ctx.Deadline(); ok {
+		task.Deadline = &deadline
+	}
+	result, err := send(ctx, task)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	if len(result.State) > 0 {
+		if err := json.Unmarshal(result.State, step); err != nil {
+			return fmt.Errorf("flowremote: restore step %q: %w", name, err)
+		}
+	}
+	return result.err(name)
+}
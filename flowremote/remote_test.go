@@ -0,0 +1,197 @@
+package flowremote_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/Azure/go-workflow/flowremote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// workerEnv makes the test binary serve one Task on stdio, as the worker of
+// SubprocessExecutor.
+const workerEnv = "FLOWREMOTE_TEST_WORKER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(workerEnv) != "" {
+		if err := flowremote.ServeStdio(context.Background(), flowremote.NewWorker(registry(new(atomic.Int32))), os.Stdin, os.Stdout); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// Greet is a Step exchanged through its exported fields.
+type Greet struct {
+	Name     string
+	Greeting string
+}
+
+func (g *Greet) Do(context.Context) error {
+	g.Greeting = "hello " + g.Name
+	return nil
+}
+
+// square is a Function; the coordinator's copy must never run.
+func square(do func(context.Context, int) (int, error)) *flow.Function[int, int] {
+	return flow.FuncIO("square", do)
+}
+
+func local(context.Context, int) (int, error) { panic("ran on the coordinator") }
+
+// registry returns the Steps of the worker; flaky fails until its third
+// attempt, counted in attempts.
+func registry(attempts *atomic.Int32) *flowremote.Registry {
+	reg := flowremote.NewRegistry()
+	reg.Register("greet", func() flow.Steper { return new(Greet) })
+	reg.Register("square", func() flow.Steper {
+		return square(func(ctx context.Context, i int) (int, error) { return i * i, nil })
+	})
+	reg.Register("flaky", func() flow.Steper {
+		return flow.Func("flaky", func(ctx context.Context) error {
+			if attempts.Add(1) < 3 {
+				return errors.New("not yet")
+			}
+			return nil
+		})
+	})
+	reg.Register("skip", func() flow.Steper {
+		return flow.Func("skip", func(ctx context.Context) error { return flow.Skip(errors.New("nothing to do")) })
+	})
+	reg.Register("wait", func() flow.Steper {
+		return flow.Func("wait", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	})
+	return reg
+}
+
+func TestHTTPExecutor(t *testing.T) {
+	t.Parallel()
+	attempts := new(atomic.Int32)
+	server := httptest.NewServer(flowremote.NewHandler(flowremote.NewWorker(registry(attempts))))
+	defer server.Close()
+	executor := &flowremote.HTTPExecutor{URL: server.URL, Registry: registry(nil)}
+
+	t.Run("state travels both ways", func(t *testing.T) {
+		greet := &Greet{}
+		sq := square(local)
+		var got int
+		w := new(flow.Workflow).Add(
+			flow.Step(greet).Input(func(ctx context.Context, g *Greet) error {
+				g.Name = "world"
+				return nil
+			}),
+			flow.Step(sq).Input(func(ctx context.Context, f *flow.Function[int, int]) error {
+				f.Input = 7
+				return nil
+			}).Output(func(ctx context.Context, f *flow.Function[int, int]) error {
+				got = f.Output
+				return nil
+			}),
+		)
+		w.Option.Executor = executor
+		require.NoError(t, w.Do(context.Background()))
+		assert.Equal(t, "hello world", greet.Greeting)
+		assert.Equal(t, 49, got)
+	})
+	t.Run("retries stay on the coordinator", func(t *testing.T) {
+		flaky := flow.Func("flaky", func(ctx context.Context) error { panic("ran on the coordinator") })
+		w := new(flow.Workflow).Add(flow.Step(flaky).Retry(func(ro *flow.RetryOption) {
+			ro.Attempts = 3
+			ro.Backoff = nil
+			ro.NextBackOff = func(context.Context, flow.RetryEvent, time.Duration) time.Duration { return 0 }
+		}))
+		w.Option.Executor = executor
+		require.NoError(t, w.Do(context.Background()))
+		assert.EqualValues(t, 3, attempts.Load())
+	})
+	t.Run("status and error come back", func(t *testing.T) {
+		skip := flow.Func("skip", func(ctx context.Context) error { return nil })
+		w := new(flow.Workflow).Add(flow.Step(skip))
+		w.Option.Executor = executor
+		require.NoError(t, w.Do(context.Background()))
+		assert.Equal(t, flow.Skipped, w.StateOf(skip).GetStatus())
+		var remote flowremote.RemoteError
+		require.ErrorAs(t, w.StateOf(skip).GetError(), &remote)
+		assert.Equal(t, "nothing to do", remote.Message)
+	})
+	t.Run("the worker stops when the step times out", func(t *testing.T) {
+		wait := flow.Func("wait", func(ctx context.Context) error { return nil })
+		w := new(flow.Workflow).Add(flow.Step(wait).Timeout(50 * time.Millisecond))
+		w.Option.Executor = executor
+		require.Error(t, w.Do(context.Background()))
+		assert.Equal(t, flow.Canceled, w.StateOf(wait).GetStatus())
+	})
+	t.Run("unregistered steps run in-process, nested workflows' steps are dispatched", func(t *testing.T) {
+		ran := false
+		localStep := flow.Func("local", func(ctx context.Context) error {
+			ran = true
+			return nil
+		})
+		greet := &Greet{Name: "nested"}
+		inner := new(flow.Workflow).Add(flow.Step(greet))
+		w := new(flow.Workflow).Add(flow.Steps(localStep, inner))
+		w.Option.Executor = executor
+		require.NoError(t, w.Do(context.Background()))
+		assert.True(t, ran)
+		assert.Equal(t, "hello nested", greet.Greeting)
+	})
+	t.Run("wrapped steps are dispatched, mocked steps run in-process", func(t *testing.T) {
+		named, mockedSq := square(local), square(local)
+		mocked := false
+		w := new(flow.Workflow).Add(
+			flow.Name(named, "renamed"),
+			flow.Mock(mockedSq, func(context.Context) error {
+				mocked = true
+				return nil
+			}),
+		)
+		named.Input = 6
+		w.Option.Executor = executor
+		require.NoError(t, w.Do(context.Background()))
+		assert.Equal(t, 36, named.Output)
+		assert.True(t, mocked)
+	})
+}
+
+func TestSubprocessExecutor(t *testing.T) {
+	t.Parallel()
+	sq := square(local)
+	w := new(flow.Workflow).Add(flow.Step(sq).Input(func(ctx context.Context, f *flow.Function[int, int]) error {
+		f.Input = 12
+		return nil
+	}))
+	w.Option.Executor = &flowremote.SubprocessExecutor{
+		Path:     os.Args[0],
+		Env:      []string{workerEnv + "=1"},
+		Registry: registry(nil),
+	}
+	require.NoError(t, w.Do(context.Background()))
+	assert.Equal(t, 144, sq.Output)
+}
+
+func TestWorker(t *testing.T) {
+	t.Parallel()
+	worker := flowremote.NewWorker(registry(nil))
+	result := worker.Execute(context.Background(), flowremote.Task{Step: "missing"})
+	assert.Equal(t, flow.Failed, result.Status)
+	assert.Contains(t, result.Error, `step "missing" not registered`)
+
+	result = worker.Execute(context.Background(), flowremote.Task{Step: "greet", State: []byte(`{"Name":"you"}`)})
+	assert.Equal(t, flow.Succeeded, result.Status)
+	assert.JSONEq(t, `{"Name":"you","Greeting":"hello you"}`, string(result.State))
+
+	assert.Panics(t, func() {
+		registry(nil).Register("greet", func() flow.Steper { return new(Greet) })
+	}, "registered twice")
+}
@@ -0,0 +1,66 @@
+package flowremote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	flow "github.com/Azure/go-workflow"
+)
+
+// SubprocessExecutor is a flow.Executor running each Step registered in
+// Registry in a new process: Path with Args and Env (appended to the
+// current environment), which reads the Task on its stdin and writes the
+// Result on its stdout, typically with ServeStdio. The process is killed
+// when the Step's context is done.
+type SubprocessExecutor struct {
+	Path     string
+	Args     []string
+	Env      []string
+	Registry *Registry
+}
+
+func (e *SubprocessExecutor) Execute(ctx context.Context, step flow.Steper) error {
+	return dispatch(ctx, e.Registry, step, e.send)
+}
+
+func (e *SubprocessExecutor) send(ctx context.Context, task Task) (Result, error) {
+	in, err := json.Marshal(task)
+	if err != nil {
+		return Result{}, err
+	}
+	cmd := exec.CommandContext(ctx, e.Path, e.Args...)
+	cmd.Env = append(os.Environ(), e.Env...)
+	cmd.Stdin = bytes.NewReader(in)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return Result{}, fmt.Errorf("flowremote: %s: %w: %s", e.Path, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	var result Result
+	if err := json.Unmarshal(out, &result); err != nil {
+		return Result{}, fmt.Errorf("flowremote: %s: %w", e.Path, err)
+	}
+	return result, nil
+}
+
+// ServeStdio is the worker side of SubprocessExecutor: it reads one Task
+// from in, runs it with w, and writes its Result to out.
+//
+//	func main() {
+//	    if err := flowremote.ServeStdio(context.Background(), worker, os.Stdin, os.Stdout); err != nil {
+//	        log.Fatal(err)
+//	    }
+//	}
+func ServeStdio(ctx context.Context, w *Worker, in io.Reader, out io.Writer) error {
+	var task Task
+	if err := json.NewDecoder(in).Decode(&task); err != nil {
+		return err
+	}
+	return json.NewEncoder(out).Encode(w.Execute(ctx, task))
+}
@@ -0,0 +1,46 @@
+// Package flowschedule runs a Workflow repeatedly, on an interval or any other
+// Schedule, with consistent handling of overlapping and missed runs:
+//
+//	s := &flowschedule.Scheduler{
+//	    Schedule: flowschedule.Every(5 * time.Minute),
+//	    Workflow: newReconciler, // func() *flow.Workflow
+//	    Overlap:  flowschedule.Skip,
+//	    Jitter:   30 * time.Second,
+//	    OnRun: func(r flowschedule.Run) {
+//	        slog.Info("reconciled", "scheduled", r.Scheduled, "fingerprint", r.Fingerprint, "err", r.Err)
+//	    },
+//	}
+//	err := s.Run(ctx) // until ctx is done
+//
+// The Scheduler never runs two Workflows at once, so Workflow may return the
+// same reusable Workflow every time (see Reuse): each Do resets it. Time is
+// read from Clock, so tests can drive a Scheduler with a clock.Mock.
+//
+// Cron expressions are parsed by the separate module
+// github.com/Azure/go-workflow/contrib/cron, so its dependency doesn't enter
+// this one.
+package flowschedule
+
+import "time"
+
+// Schedule tells when a Workflow is due next. Next returns the first due
+// time after t, or the zero time if there is none anymore.
+//
+// Every builds Schedules; so does flowcron.Parse, and any
+// github.com/robfig/cron/v3 Schedule is one too.
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+// Every returns a Schedule due every d, counted from the time the Scheduler
+// starts (or Scheduler.Since). It panics if d isn't positive.
+func Every(d time.Duration) Schedule {
+	if d <= 0 {
+		panic("flowschedule: non-positive interval for Every")
+	}
+	return every(d)
+}
+
+type every time.Duration
+
+func (d every) Next(t time.Time) time.Time { return t.Add(time.Duration(d)) }
@@ -0,0 +1,294 @@
+package flowschedule
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/benbjohnson/clock"
+)
+
+// Overlap is what a Scheduler does with a run due while the previous one is
+// still running.
+type Overlap int
+
+const (
+	// Skip skips the due run, recorded with ErrOverlap.
+	Skip Overlap = iota
+	// Queue starts the due run once the runs before it finished, in order.
+	// At most QueueLimit runs wait: once runs take longer than the Schedule's
+	// interval, the due runs beyond it are skipped with ErrOverlap.
+	Queue
+	// CancelPrevious cancels the running Workflow's context with
+	// ErrCanceledByNext, and starts the due run once it returned. A run
+	// waiting for that replaces the one waiting before, which is skipped
+	// with ErrOverlap.
+	CancelPrevious
+)
+
+// CatchUp is what a Scheduler does with the runs it missed: due times
+// passed while it couldn't start them, because Since is in the past, or the
+// process was suspended, or the Clock jumped.
+type CatchUp int
+
+const (
+	// CatchUpLatest runs the latest missed run only; the others are
+	// recorded with ErrMissed.
+	CatchUpLatest CatchUp = iota
+	// CatchUpAll runs every missed run, oldest first, subject to Overlap:
+	// use Queue to run them one after the other.
+	CatchUpAll
+)
+
+var (
+	// ErrOverlap is the Err of a Run skipped because of Overlap.
+	ErrOverlap = errors.New("flowschedule: skipped, the previous run is still running")
+	// ErrMissed is the Err of a missed Run not caught up, see CatchUp.
+	ErrMissed = errors.New("flowschedule: skipped, missed run")
+	// ErrCanceledByNext is the cause of the cancellation of a run by the next
+	// one, see CancelPrevious.
+	ErrCanceledByNext = errors.New("flowschedule: canceled by the next run")
+	// ErrSchedulerIsRunning is returned by Scheduler.Run while another call
+	// is in flight.
+	ErrSchedulerIsRunning = errors.New("flowschedule: scheduler is running")
+)
+
+// DefaultHistoryLimit is the number of Runs a Scheduler keeps when its
+// HistoryLimit is zero.
+const DefaultHistoryLimit = 100
+
+// DefaultQueueLimit is the number of runs a Scheduler queues when its
+// QueueLimit is zero, see Queue.
+const DefaultQueueLimit = 10
+
+// Scheduler runs Workflows on a Schedule. Set its fields, then call Run.
+type Scheduler struct {
+	// Schedule tells when runs are due. Required.
+	Schedule Schedule
+	// Workflow returns the Workflow to run, once per run. Required; see Reuse.
+	Workflow func() *flow.Workflow
+
+	Overlap Overlap
+	// QueueLimit caps how many due runs wait with Queue, including the missed
+	// runs of CatchUpAll. Zero means DefaultQueueLimit.
+	QueueLimit int
+	CatchUp    CatchUp
+	// Jitter delays each run by a random duration in [0, Jitter), to spread
+	// the load of many Schedulers due at the same time. Missed runs start
+	// without delay.
+	Jitter time.Duration
+	// Since is the time the Schedule starts from, e.g. the last run before a
+	// restart, so runs due in between are caught up. Zero means the time Run
+	// is called.
+	Since time.Time
+	// Clock reads the time and waits. nil means the wall clock.
+	Clock clock.Clock
+	// HistoryLimit caps how many Runs History keeps, the most recent ones.
+	// Zero means DefaultHistoryLimit.
+	HistoryLimit int
+	// OnRun, if set, is called with each Run once it's finished or skipped,
+	// e.g. to log it.
+	OnRun func(Run)
+
+	running sync.Mutex // single-runner guard, see Run.
+	mu      sync.Mutex
+	history []Run
+}
+
+// Run is one run of a Scheduler, finished or skipped.
+type Run struct {
+	Scheduled time.Time // the due time, from the Schedule.
+	Started   time.Time // zero if skipped.
+	Finished  time.Time // zero if skipped.
+	// Fingerprint is the flow.Workflow.Fingerprint of the Workflow run.
+	Fingerprint string
+	// Err is what Workflow.Do returned — an ErrWorkflow if some Steps
+	// failed, see Run.ErrWorkflow — or why the run was skipped: ErrOverlap,
+	// ErrMissed, or the cause of the Scheduler's context.
+	Err error
+}
+
+// Skipped reports whether the run didn't start.
+func (r Run) Skipped() bool { return r.Started.IsZero() }
+
+// ErrWorkflow returns the flow.ErrWorkflow in Err, nil if none.
+func (r Run) ErrWorkflow() flow.ErrWorkflow {
+	var err flow.ErrWorkflow
+	errors.As(r.Err, &err)
+	return err
+}
+
+// Reuse returns a Workflow func for Scheduler running w every time.
+func Reuse(w *flow.Workflow) func() *flow.Workflow {
+	return func() *flow.Workflow { return w }
+}
+
+// History returns the recorded Runs, oldest first.
+func (s *Scheduler) History() []Run {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Run(nil), s.history...)
+}
+
+// Run runs the Workflows as they are due, one at a time, until ctx is done
+// or the Schedule has no due time anymore. The running Workflow gets a
+// context derived from ctx: once ctx is done, Run waits for it to return,
+// records the runs still queued as skipped, and returns the cause of ctx.
+// It returns nil once the Schedule is over and the last run finished.
+func (s *Scheduler) Run(ctx context.Context) error {
+	if s.Schedule == nil || s.Workflow == nil {
+		return errors.New("flowschedule: Schedule and Workflow are required")
+	}
+	if !s.running.TryLock() {
+		return ErrSchedulerIsRunning
+	}
+	defer s.running.Unlock()
+
+	clk := s.clock()
+	since := s.Since
+	if since.IsZero() {
+		since = clk.Now()
+	}
+	next := s.Schedule.Next(since)
+	startAt := next.Add(s.jitter())
+
+	var (
+		cancel   context.CancelCauseFunc // cancels the running Workflow; nil if none runs.
+		queue    []time.Time             // due times waiting for the running Workflow.
+		finished = make(chan Run)
+	)
+	start := func(due time.Time) {
+		runCtx, cancelRun := context.WithCancelCause(ctx)
+		cancel = cancelRun
+		go func() {
+			defer cancelRun(nil)
+			finished <- s.do(runCtx, clk, due)
+		}()
+	}
+	trigger := func(due time.Time) {
+		switch {
+		case cancel == nil:
+			start(due)
+		case s.Overlap == Queue && len(queue) < s.queueLimit():
+			queue = append(queue, due)
+		case s.Overlap == CancelPrevious:
+			cancel(ErrCanceledByNext)
+			for _, skipped := range queue {
+				s.record(Run{Scheduled: skipped, Err: ErrOverlap})
+			}
+			queue = append(queue[:0], due)
+		default: // Skip, or Queue is full.
+			s.record(Run{Scheduled: due, Err: ErrOverlap})
+		}
+	}
+	fired := make(chan time.Time)
+	close(fired)
+	for {
+		if next.IsZero() && cancel == nil && len(queue) == 0 {
+			return nil
+		}
+		var timer *clock.Timer
+		var due <-chan time.Time
+		if !next.IsZero() {
+			if wait := startAt.Sub(clk.Now()); wait > 0 {
+				timer = clk.Timer(wait)
+				due = timer.C
+			} else {
+				due = fired
+			}
+		}
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			if cancel != nil {
+				s.record(<-finished)
+			}
+			for _, skipped := range queue {
+				s.record(Run{Scheduled: skipped, Err: context.Cause(ctx)})
+			}
+			return context.Cause(ctx)
+		case r := <-finished:
+			s.record(r)
+			cancel = nil
+			if len(queue) > 0 {
+				start(queue[0])
+				queue = queue[1:]
+			}
+		case <-due:
+			now := clk.Now()
+			dues := []time.Time{next}
+			for next = s.Schedule.Next(next); !next.IsZero() && !next.After(now); next = s.Schedule.Next(next) {
+				dues = append(dues, next)
+			}
+			startAt = next.Add(s.jitter())
+			if s.CatchUp == CatchUpLatest {
+				for _, missed := range dues[:len(dues)-1] {
+					s.record(Run{Scheduled: missed, Err: ErrMissed})
+				}
+				dues = dues[len(dues)-1:]
+			}
+			for _, d := range dues {
+				trigger(d)
+			}
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// do runs the Workflow due at due.
+func (s *Scheduler) do(ctx context.Context, clk clock.Clock, due time.Time) Run {
+	r := Run{Scheduled: due, Started: clk.Now()}
+	if w := s.Workflow(); w == nil {
+		r.Err = errors.New("flowschedule: Workflow returned nil")
+	} else {
+		r.Fingerprint = w.Fingerprint()
+		r.Err = w.Do(ctx)
+	}
+	r.Finished = clk.Now()
+	return r
+}
+
+// record appends r to the history and reports it to OnRun.
+func (s *Scheduler) record(r Run) {
+	limit := s.HistoryLimit
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	s.mu.Lock()
+	s.history = append(s.history, r)
+	if len(s.history) > limit {
+		s.history = append(s.history[:0], s.history[len(s.history)-limit:]...)
+	}
+	s.mu.Unlock()
+	if s.OnRun != nil {
+		s.OnRun(r)
+	}
+}
+
+func (s *Scheduler) clock() clock.Clock {
+	if s.Clock == nil {
+		return clock.New()
+	}
+	return s.Clock
+}
+
+func (s *Scheduler) queueLimit() int {
+	if s.QueueLimit <= 0 {
+		return DefaultQueueLimit
+	}
+	return s.QueueLimit
+}
+
+func (s *Scheduler) jitter() time.Duration {
+	if s.Jitter <= 0 {
+		return 0
+	}
+	return rand.N(s.Jitter)
+}
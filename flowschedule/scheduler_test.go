@@ -0,0 +1,301 @@
+package flowschedule_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/Azure/go-workflow/flowschedule"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// harness runs a Scheduler on a mock clock. Its Workflow has a single step
+// which reports when it starts and returns once released, or its context is
+// done.
+type harness struct {
+	t       *testing.T
+	clock   *clock.Mock
+	s       *flowschedule.Scheduler
+	started chan time.Time
+	release chan error
+	done    chan error
+	cancel  context.CancelFunc
+	stopped sync.Once
+	err     error
+}
+
+func newHarness(t *testing.T, configure func(*flowschedule.Scheduler)) *harness {
+	t.Helper()
+	h := &harness{
+		t:       t,
+		clock:   clock.NewMock(),
+		started: make(chan time.Time, 10),
+		release: make(chan error, 10),
+		done:    make(chan error, 1),
+	}
+	w := new(flow.Workflow).Add(flow.Step(flow.Func("step", func(ctx context.Context) error {
+		h.started <- h.clock.Now()
+		select {
+		case err := <-h.release:
+			return err
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		}
+	})))
+	h.s = &flowschedule.Scheduler{
+		Schedule: flowschedule.Every(time.Minute),
+		Workflow: flowschedule.Reuse(w),
+		Clock:    h.clock,
+		Since:    h.clock.Now(),
+	}
+	if configure != nil {
+		configure(h.s)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	go func() { h.done <- h.s.Run(ctx) }()
+	t.Cleanup(func() { h.stop() })
+	return h
+}
+
+// stop cancels the Scheduler's context, and returns what Run returned.
+func (h *harness) stop() error {
+	h.stopped.Do(func() {
+		h.cancel()
+		h.err = <-h.done
+	})
+	return h.err
+}
+
+// advance moves the clock forward by d.
+func (h *harness) advance(d time.Duration) { h.clock.Add(d) }
+
+// waitStarted waits for the next run to start.
+func (h *harness) waitStarted() time.Time {
+	h.t.Helper()
+	select {
+	case at := <-h.started:
+		return at
+	case <-time.After(time.Second):
+		require.FailNow(h.t, "no run started")
+		return time.Time{}
+	}
+}
+
+// waitHistory waits until n Runs are recorded, and returns them.
+func (h *harness) waitHistory(n int) []flowschedule.Run {
+	h.t.Helper()
+	require.Eventually(h.t, func() bool { return len(h.s.History()) >= n }, time.Second, time.Millisecond)
+	return h.s.History()
+}
+
+func TestScheduler_Skip(t *testing.T) {
+	t.Parallel()
+	h := newHarness(t, nil)
+	start := h.clock.Now()
+	h.advance(time.Minute)
+	h.waitStarted()
+	h.advance(time.Minute) // due while the first run is running
+	history := h.waitHistory(1)
+	assert.Equal(t, start.Add(2*time.Minute), history[0].Scheduled)
+	assert.ErrorIs(t, history[0].Err, flowschedule.ErrOverlap)
+	assert.True(t, history[0].Skipped())
+
+	h.release <- nil
+	history = h.waitHistory(2)
+	assert.Equal(t, start.Add(time.Minute), history[1].Scheduled)
+	assert.NoError(t, history[1].Err)
+	assert.False(t, history[1].Skipped())
+	assert.NotEmpty(t, history[1].Fingerprint)
+
+	h.release <- errors.New("boom")
+	h.advance(time.Minute)
+	h.waitStarted()
+	history = h.waitHistory(3)
+	assert.ErrorContains(t, history[2].Err, "boom")
+	assert.Len(t, history[2].ErrWorkflow(), 1)
+	assert.ErrorIs(t, h.stop(), context.Canceled)
+}
+
+func TestScheduler_Queue(t *testing.T) {
+	t.Parallel()
+	h := newHarness(t, func(s *flowschedule.Scheduler) {
+		s.Overlap = flowschedule.Queue
+		s.CatchUp = flowschedule.CatchUpAll // the two due times may be seen at once
+	})
+	start := h.clock.Now()
+	h.advance(time.Minute)
+	h.waitStarted()
+	h.advance(time.Minute)
+	h.advance(time.Minute)
+	for range 3 {
+		h.release <- nil
+	}
+	h.waitStarted()
+	h.waitStarted()
+	history := h.waitHistory(3)
+	for i, r := range history {
+		assert.Equal(t, start.Add(time.Duration(i+1)*time.Minute), r.Scheduled)
+		assert.NoError(t, r.Err)
+	}
+}
+
+func TestScheduler_QueueLimit(t *testing.T) {
+	t.Parallel()
+	h := newHarness(t, func(s *flowschedule.Scheduler) {
+		s.Overlap = flowschedule.Queue
+		s.QueueLimit = 1
+		s.CatchUp = flowschedule.CatchUpAll
+	})
+	start := h.clock.Now()
+	h.advance(time.Minute)
+	h.waitStarted()
+	h.advance(time.Minute) // queued
+	h.advance(time.Minute) // the queue is full
+	history := h.waitHistory(1)
+	assert.Equal(t, start.Add(3*time.Minute), history[0].Scheduled)
+	assert.ErrorIs(t, history[0].Err, flowschedule.ErrOverlap)
+
+	h.release <- nil
+	h.release <- nil
+	h.waitStarted()
+	history = h.waitHistory(3)
+	assert.Equal(t, start.Add(time.Minute), history[1].Scheduled)
+	assert.Equal(t, start.Add(2*time.Minute), history[2].Scheduled)
+	assert.NoError(t, history[2].Err)
+}
+
+func TestScheduler_CancelPrevious(t *testing.T) {
+	t.Parallel()
+	h := newHarness(t, func(s *flowschedule.Scheduler) { s.Overlap = flowschedule.CancelPrevious })
+	h.advance(time.Minute)
+	h.waitStarted()
+	h.advance(time.Minute)
+	h.waitStarted()
+	history := h.waitHistory(1)
+	assert.ErrorIs(t, history[0].Err, flowschedule.ErrCanceledByNext)
+	h.release <- nil
+	history = h.waitHistory(2)
+	assert.NoError(t, history[1].Err)
+}
+
+func TestScheduler_CatchUp(t *testing.T) {
+	t.Parallel()
+	t.Run("latest", func(t *testing.T) {
+		h := newHarness(t, func(s *flowschedule.Scheduler) {
+			s.Since = s.Clock.Now().Add(-10 * time.Minute)
+			s.Schedule = flowschedule.Every(3 * time.Minute)
+		})
+		h.release <- nil
+		h.waitStarted()
+		history := h.waitHistory(3)
+		now := h.clock.Now()
+		assert.ErrorIs(t, history[0].Err, flowschedule.ErrMissed)
+		assert.Equal(t, now.Add(-7*time.Minute), history[0].Scheduled)
+		assert.ErrorIs(t, history[1].Err, flowschedule.ErrMissed)
+		assert.Equal(t, now.Add(-time.Minute), history[2].Scheduled)
+		assert.NoError(t, history[2].Err)
+	})
+	t.Run("all", func(t *testing.T) {
+		h := newHarness(t, func(s *flowschedule.Scheduler) {
+			s.Since = s.Clock.Now().Add(-10 * time.Minute)
+			s.Schedule = flowschedule.Every(3 * time.Minute)
+			s.CatchUp = flowschedule.CatchUpAll
+			s.Overlap = flowschedule.Queue
+		})
+		for range 3 {
+			h.release <- nil
+			h.waitStarted()
+		}
+		history := h.waitHistory(3)
+		for _, r := range history {
+			assert.NoError(t, r.Err)
+		}
+	})
+}
+
+func TestScheduler_Jitter(t *testing.T) {
+	t.Parallel()
+	h := newHarness(t, func(s *flowschedule.Scheduler) { s.Jitter = 30 * time.Second })
+	h.release <- nil
+	due := h.clock.Now().Add(time.Minute)
+	h.advance(time.Minute)
+	var started time.Time
+	for started.IsZero() {
+		select {
+		case started = <-h.started:
+		case <-time.After(10 * time.Millisecond):
+			h.advance(time.Second)
+		}
+	}
+	assert.False(t, started.Before(due))
+	assert.Less(t, started.Sub(due), 31*time.Second)
+}
+
+func TestScheduler_HistoryLimit(t *testing.T) {
+	t.Parallel()
+	var reported []flowschedule.Run
+	h := newHarness(t, func(s *flowschedule.Scheduler) {
+		s.HistoryLimit = 2
+		s.OnRun = func(r flowschedule.Run) { reported = append(reported, r) }
+	})
+	h.advance(time.Minute)
+	h.waitStarted()
+	for range 3 {
+		h.advance(time.Minute) // skipped
+	}
+	history := h.waitHistory(2)
+	require.Eventually(t, func() bool { return len(h.s.History()) == 2 && h.s.History()[1].Scheduled.Equal(h.clock.Now()) },
+		time.Second, time.Millisecond)
+	assert.Len(t, history, 2)
+	h.release <- nil
+	h.stop()
+	assert.Len(t, reported, 4)
+}
+
+// once is due a single time.
+type once time.Time
+
+func (o once) Next(t time.Time) time.Time {
+	if t.Before(time.Time(o)) {
+		return time.Time(o)
+	}
+	return time.Time{}
+}
+
+func TestScheduler_ScheduleEnds(t *testing.T) {
+	t.Parallel()
+	clk := clock.NewMock()
+	ran := 0
+	s := &flowschedule.Scheduler{
+		Schedule: once(clk.Now()),
+		Workflow: func() *flow.Workflow {
+			return new(flow.Workflow).Add(flow.Step(flow.Func("step", func(context.Context) error {
+				ran++
+				return nil
+			})))
+		},
+		Clock: clk,
+		Since: clk.Now().Add(-time.Second),
+	}
+	assert.NoError(t, s.Run(context.Background()))
+	assert.Equal(t, 1, ran)
+	assert.Len(t, s.History(), 1)
+}
+
+func TestScheduler_Errors(t *testing.T) {
+	t.Parallel()
+	assert.Error(t, new(flowschedule.Scheduler).Run(context.Background()))
+	assert.Panics(t, func() { flowschedule.Every(0) })
+}
+
+func TestEvery(t *testing.T) {
+	t.Parallel()
+	at := time.Date(2026, 10, 18, 12, 3, 0, 0, time.UTC)
+	assert.Equal(t, at.Add(90*time.Second), flowschedule.Every(90*time.Second).Next(at))
+}
@@ -0,0 +1,283 @@
+package flowtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/benbjohnson/clock"
+)
+
+// Chaos is a fault injector for exercising a Workflow's failure handling
+// without editing Step code. It is a flow.AttemptInterceptor: install it
+// with Install (or add it to Option.AttemptInterceptors yourself) and it
+// injects the configured faults into matching attempts.
+//
+//	chaos := flowtest.NewChaos(42)
+//	chaos.On(flowtest.StepType[*CreateVM]()).Fail(errThrottled).OnAttempts(0, 1)
+//	chaos.On(flowtest.StepNamed("notify")).Delay(time.Second).Probability(0.5)
+//	chaos.Install(w)
+//
+//	err := w.Do(ctx)
+//	// assert recovery, then inspect chaos.Injected()
+//
+// Faults are chosen per attempt, so Retry policies see them exactly like
+// real failures. Probabilities draw from a RNG seeded by NewChaos; combine
+// with flowtest.New (Sequential) so the draws happen in the same order on
+// every run.
+type Chaos struct {
+	// Clock is used to wait for injected latency. nil means the wall clock;
+	// Install defaults it to the Workflow's Option.Clock, e.g. the mock
+	// clock of flowtest.New.
+	Clock clock.Clock
+
+	mu       sync.Mutex
+	rng      *rand.Rand
+	rules    []*FaultRule
+	injected []InjectedFault
+}
+
+// NewChaos returns a Chaos whose probabilities are drawn from a RNG
+// seeded with seed.
+func NewChaos(seed uint64) *Chaos {
+	return &Chaos{rng: rand.New(rand.NewPCG(seed, seed))}
+}
+
+// FaultKind names the kind of an injected fault.
+type FaultKind string
+
+const (
+	FaultError   FaultKind = "error"   // the attempt returns an error without running.
+	FaultPanic   FaultKind = "panic"   // the attempt panics without running.
+	FaultLatency FaultKind = "latency" // the attempt starts after a delay.
+	FaultCancel  FaultKind = "cancel"  // the attempt runs with a canceled context.
+)
+
+// InjectedFault records one fault injected into one attempt.
+type InjectedFault struct {
+	Step    flow.Steper // the Step as seen by the interceptor (the root step).
+	Attempt uint64      // 0-based attempt index.
+	Kind    FaultKind
+}
+
+// ErrInjectedCancel is the context cancellation cause of attempts that
+// received a FaultCancel.
+var ErrInjectedCancel = errors.New("flowtest: injected cancellation")
+
+// StepMatcher selects the Steps a FaultRule applies to.
+type StepMatcher func(flow.Steper) bool
+
+// StepType matches Steps whose Unwrap chain contains a T, using the same
+// rule as flow.Mutate: the walk does not descend into nested workflows,
+// whose Steps are matched when the sub-workflow runs them (Chaos is
+// inherited through Option.AttemptInterceptors).
+func StepType[T flow.Steper]() StepMatcher {
+	return func(step flow.Steper) bool {
+		return matchLayer(step, func(s flow.Steper) bool {
+			_, ok := s.(T)
+			return ok
+		})
+	}
+}
+
+// StepNamed matches Steps whose Unwrap chain contains a layer with
+// flow.String(layer) == name, without descending into nested workflows.
+func StepNamed(name string) StepMatcher {
+	return func(step flow.Steper) bool {
+		return matchLayer(step, func(s flow.Steper) bool { return flow.String(s) == name })
+	}
+}
+
+// matchLayer walks step's Unwrap chain until match succeeds or a nested
+// workflow boundary is reached.
+func matchLayer(step flow.Steper, match func(flow.Steper) bool) bool {
+	matched := false
+	flow.Traverse(step, func(s flow.Steper, walked []flow.Steper) flow.TraverseDecision {
+		if match(s) {
+			matched = true
+			return flow.TraverseStop
+		}
+		if _, isWorkflow := s.(interface {
+			StateOf(flow.Steper) *flow.State
+		}); isWorkflow {
+			return flow.TraverseEndBranch
+		}
+		return flow.TraverseContinue
+	})
+	return matched
+}
+
+// FaultRule is one "inject this into those Steps" entry, created by
+// Chaos.On and configured by chaining. A rule may combine faults: latency
+// is applied first, then cancellation, then a panic or an error.
+type FaultRule struct {
+	match       StepMatcher
+	err         error
+	panicValue  any
+	latency     time.Duration
+	cancel      bool
+	probability float64
+	attempts    []uint64
+}
+
+// On adds a rule for the Steps selected by match. Until configured with
+// Probability or OnAttempts, the rule fires on every attempt.
+func (c *Chaos) On(match StepMatcher) *FaultRule {
+	r := &FaultRule{match: match, probability: 1}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rules = append(c.rules, r)
+	return r
+}
+
+// Fail makes the attempt return err instead of running.
+func (r *FaultRule) Fail(err error) *FaultRule {
+	r.err = err
+	return r
+}
+
+// Panic makes the attempt panic with v instead of running.
+func (r *FaultRule) Panic(v any) *FaultRule {
+	r.panicValue = v
+	return r
+}
+
+// Delay makes the attempt wait d (on Chaos.Clock) before running. The wait
+// ends early, with the context's error, if the attempt's context is done.
+func (r *FaultRule) Delay(d time.Duration) *FaultRule {
+	r.latency = d
+	return r
+}
+
+// Cancel makes the attempt run with an already-canceled context whose
+// cause is ErrInjectedCancel.
+func (r *FaultRule) Cancel() *FaultRule {
+	r.cancel = true
+	return r
+}
+
+// Probability makes the rule fire on each eligible attempt with
+// probability p, in [0, 1].
+func (r *FaultRule) Probability(p float64) *FaultRule {
+	r.probability = p
+	return r
+}
+
+// OnAttempts restricts the rule to the given 0-based attempt indexes.
+func (r *FaultRule) OnAttempts(attempts ...uint64) *FaultRule {
+	r.attempts = append(r.attempts, attempts...)
+	return r
+}
+
+// Install appends c to w.Option.AttemptInterceptors. Unless configured
+// already, it also sets c.Clock to w.Option.Clock, so call it after
+// flowtest.New to delay on the Harness's mock clock, and w.Option.DontPanic
+// to true, so Panic faults fail their Step instead of crashing the test
+// binary.
+func (c *Chaos) Install(w *flow.Workflow) {
+	w.Option.AttemptInterceptors = append(w.Option.AttemptInterceptors, c)
+	if c.Clock == nil {
+		c.Clock = w.Option.Clock
+	}
+	if w.Option.DontPanic == nil {
+		dontPanic := true
+		w.Option.DontPanic = &dontPanic
+	}
+}
+
+// Injected returns every fault injected so far, in injection order.
+func (c *Chaos) Injected() []InjectedFault {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.injected)
+}
+
+// InjectedInto returns the faults injected into step (or into a Step
+// containing it).
+func (c *Chaos) InjectedInto(step flow.Steper) []InjectedFault {
+	var rv []InjectedFault
+	for _, f := range c.Injected() {
+		if flow.HasStep(f.Step, step) {
+			rv = append(rv, f)
+		}
+	}
+	return rv
+}
+
+// fire picks the rules firing for this attempt. It holds the lock for the
+// RNG draws so they are serialized.
+func (c *Chaos) fire(step flow.Steper, attempt uint64) []*FaultRule {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var fired []*FaultRule
+	for _, r := range c.rules {
+		if r.match == nil || !r.match(step) {
+			continue
+		}
+		if len(r.attempts) > 0 && !slices.Contains(r.attempts, attempt) {
+			continue
+		}
+		if r.probability < 1 && (c.rng == nil || c.rng.Float64() >= r.probability) {
+			continue
+		}
+		fired = append(fired, r)
+	}
+	return fired
+}
+
+// record records a fault as it is injected.
+func (c *Chaos) record(step flow.Steper, attempt uint64, kind FaultKind) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.injected = append(c.injected, InjectedFault{Step: step, Attempt: attempt, Kind: kind})
+}
+
+// InterceptAttempt implements flow.AttemptInterceptor.
+func (c *Chaos) InterceptAttempt(ctx context.Context, step flow.Steper, attempt uint64, next func(context.Context) error) error {
+	fired := c.fire(step, attempt)
+	for _, r := range fired {
+		if r.latency > 0 {
+			c.record(step, attempt, FaultLatency)
+			clk := c.Clock
+			if clk == nil {
+				clk = clock.New()
+			}
+			timer := clk.Timer(r.latency)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+	for _, r := range fired {
+		if r.cancel {
+			c.record(step, attempt, FaultCancel)
+			var cancel context.CancelCauseFunc
+			ctx, cancel = context.WithCancelCause(ctx)
+			cancel(ErrInjectedCancel)
+		}
+	}
+	for _, r := range fired {
+		switch {
+		case r.panicValue != nil:
+			c.record(step, attempt, FaultPanic)
+			panic(r.panicValue)
+		case r.err != nil:
+			c.record(step, attempt, FaultError)
+			return r.err
+		}
+	}
+	return next(ctx)
+}
+
+// String renders an InjectedFault for logs and test failures.
+func (f InjectedFault) String() string {
+	return fmt.Sprintf("%s: %s on attempt %d", flow.String(f.Step), f.Kind, f.Attempt)
+}
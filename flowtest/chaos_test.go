@@ -0,0 +1,138 @@
+package flowtest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/Azure/go-workflow/flowtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChaos_FailOnAttempts(t *testing.T) {
+	t.Parallel()
+	errThrottled := errors.New("throttled")
+	vm := &createVM{}
+	w := new(flow.Workflow)
+	w.Add(flow.Step(vm).Retry(func(ro *flow.RetryOption) {
+		ro.Attempts = 3
+		ro.Timer = zeroTimer{}
+	}))
+	flowtest.Stub(w, func(ctx context.Context, vm *createVM) error { return nil })
+	h := flowtest.New(t, w)
+	chaos := flowtest.NewChaos(1)
+	chaos.On(flowtest.StepType[*createVM]()).Fail(errThrottled).OnAttempts(0, 1)
+	chaos.Install(w)
+
+	assert.NoError(t, h.Do(context.Background()), "third attempt recovers")
+	h.AssertAttempts(vm, 3)
+	injected := chaos.InjectedInto(vm)
+	if assert.Len(t, injected, 2) {
+		assert.Equal(t, flowtest.FaultError, injected[0].Kind)
+		assert.EqualValues(t, 0, injected[0].Attempt)
+		assert.EqualValues(t, 1, injected[1].Attempt)
+	}
+}
+
+func TestChaos_PanicAndCancel(t *testing.T) {
+	t.Parallel()
+	var cause error
+	observe := flow.Func("observe", func(ctx context.Context) error {
+		cause = context.Cause(ctx)
+		return ctx.Err()
+	})
+	boom := flow.Func("boom", func(ctx context.Context) error { return nil })
+	w := new(flow.Workflow)
+	w.Add(flow.Steps(observe, boom))
+	chaos := flowtest.NewChaos(1)
+	chaos.On(flowtest.StepNamed("observe")).Cancel()
+	chaos.On(flowtest.StepNamed("boom")).Panic("injected panic")
+	chaos.Install(w) // sets DontPanic
+	h := flowtest.New(t, w)
+
+	err := h.Do(context.Background())
+	var errPanic flow.ErrPanic
+	assert.ErrorAs(t, err, &errPanic)
+	assert.ErrorIs(t, cause, flowtest.ErrInjectedCancel)
+	h.AssertStatuses(map[flow.Steper]flow.StepStatus{
+		observe: flow.Canceled,
+		boom:    flow.Failed,
+	})
+	assert.Len(t, chaos.Injected(), 2)
+}
+
+func TestChaos_Delay(t *testing.T) {
+	t.Parallel()
+	step := flow.Func("slow", func(ctx context.Context) error { return nil })
+	w := new(flow.Workflow)
+	w.Add(flow.Step(step))
+	h := flowtest.New(t, w)
+	chaos := flowtest.NewChaos(1)
+	chaos.On(flowtest.StepNamed("slow")).Delay(time.Minute)
+	chaos.Install(w) // delays on h.Clock
+
+	done := make(chan error, 1)
+	go func() { done <- h.Do(context.Background()) }()
+	for {
+		time.Sleep(time.Millisecond)
+		h.Clock.Add(time.Minute)
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+			assert.Equal(t, flowtest.FaultLatency, chaos.Injected()[0].Kind)
+			return
+		default:
+		}
+	}
+}
+
+func TestChaos_DelayAborted(t *testing.T) {
+	t.Parallel()
+	step := flow.Func("slow", func(ctx context.Context) error { return nil })
+	w := new(flow.Workflow)
+	w.Add(flow.Step(step))
+	h := flowtest.New(t, w)
+	chaos := flowtest.NewChaos(1)
+	chaos.On(flowtest.StepNamed("slow")).Delay(time.Minute).Fail(errors.New("boom"))
+	chaos.Install(w)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- h.Do(ctx) }()
+	assert.Eventually(t, func() bool { return len(chaos.Injected()) > 0 }, time.Second, time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	injected := chaos.Injected()
+	if assert.Len(t, injected, 1, "the error is never injected") {
+		assert.Equal(t, flowtest.FaultLatency, injected[0].Kind)
+	}
+}
+
+func TestChaos_ProbabilityIsReproducible(t *testing.T) {
+	t.Parallel()
+	run := func() []flowtest.InjectedFault {
+		w := new(flow.Workflow)
+		for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+			w.Add(flow.Step(flow.Func(name, func(ctx context.Context) error { return nil })))
+		}
+		flowtest.New(t, w)
+		chaos := flowtest.NewChaos(7)
+		chaos.On(func(flow.Steper) bool { return true }).Fail(errors.New("boom")).Probability(0.5)
+		chaos.Install(w)
+		_ = w.Do(context.Background())
+		return chaos.Injected()
+	}
+	names := func(fs []flowtest.InjectedFault) []string {
+		var rv []string
+		for _, f := range fs {
+			rv = append(rv, f.String())
+		}
+		return rv
+	}
+	first := names(run())
+	assert.NotEmpty(t, first)
+	assert.Less(t, len(first), 8)
+	assert.Equal(t, first, names(run()))
+}
@@ -0,0 +1,52 @@
+// Package flowtest is a test harness for go-workflow.
+//
+// Concurrent workflows are awkward to test: ready Steps run in their own
+// goroutines, so any assertion that depends on execution order is racy.
+// flowtest wires a Workflow for reproducible runs and records what
+// happened so tests can assert on it afterwards:
+//
+//	func TestDeploy(t *testing.T) {
+//	    w := buildDeploy() // production code
+//	    h := flowtest.New(t, w)
+//	    flowtest.Stub(w, func(ctx context.Context, s *PublishStep) error {
+//	        return nil // never hit the real registry
+//	    })
+//
+//	    err := h.Do(context.Background())
+//
+//	    h.AssertRanBefore(build, publish)
+//	    h.AssertStatus(publish, flow.Succeeded)
+//	    h.AssertAttempts(build, 1)
+//	    flowtest.AssertGolden(t, "deploy", flowtest.Snapshot(err))
+//	}
+//
+// # What New changes on the Workflow
+//
+//   - Option.Sequential is set, so Steps run one at a time and ready Steps
+//     are dispatched in flow.String order (see
+//     flow.WorkflowOption.Sequential). Sub-workflows inherit it.
+//   - Option.Clock is set to a *clock.Mock (unless a Clock is already
+//     configured), exposed as Harness.Clock. Advance it from another
+//     goroutine to fire Step timeouts or per-try timeouts.
+//   - A StepInterceptor and an AttemptInterceptor are appended to record
+//     the start / finish order and the attempt count of every Step,
+//     including Steps of sub-workflows.
+//
+// Steps settled inline by their Condition (Skipped / Canceled) never reach
+// the interceptor chain, so they are absent from the recorded order; assert
+// on them with AssertStatus instead.
+//
+// # Fault injection
+//
+// Chaos injects errors, panics, latency or context cancellation into the
+// attempts of Steps selected by type (StepType) or name (StepNamed), on
+// given attempt numbers or with a seeded probability, and records every
+// fault it injected so tests can assert how the Workflow recovered.
+//
+// # Golden files
+//
+// Snapshot renders an error (typically the flow.ErrWorkflow returned by
+// Do) as stable text, ordered by Step name instead of finish time.
+// AssertGolden compares it with testdata/<name>.golden; run the tests with
+// FLOWTEST_UPDATE=1 to (re)write the golden files.
+package flowtest
@@ -0,0 +1,185 @@
+package flowtest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/Azure/go-workflow/flowtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeT captures failures reported by the harness so tests can assert that
+// a helper rejects a wrong expectation.
+type fakeT struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fakeT) Helper()                       {}
+func (f *fakeT) Errorf(string, ...interface{}) { f.failed = true }
+
+type createVM struct {
+	ID string
+}
+
+func (c *createVM) Do(context.Context) error { return errors.New("real createVM called") }
+
+func TestHarness_Order(t *testing.T) {
+	t.Parallel()
+	var order []string
+	record := func(name string) *flow.Function[struct{}, struct{}] {
+		return flow.Func(name, func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+	a, b, c, d := record("a"), record("b"), record("c"), record("d")
+	w := new(flow.Workflow)
+	w.Add(
+		flow.Steps(c, a, b),
+		flow.Steps(d).DependsOn(a, b, c),
+	)
+	h := flowtest.New(t, w)
+	for i := 0; i < 10; i++ {
+		order = nil
+		assert.NoError(t, h.Do(context.Background()))
+		assert.Equal(t, []string{"a", "b", "c", "d"}, order, "sequential runs follow name order")
+		assert.Equal(t, []flow.Steper{a, b, c, d}, h.Order())
+	}
+	h.AssertRanBefore(a, b)
+	h.AssertRanBefore(c, d)
+
+	ft := &fakeT{TB: t}
+	hf := flowtest.New(ft, w)
+	assert.NoError(t, hf.Do(context.Background()))
+	assert.False(t, hf.AssertRanBefore(d, a))
+	assert.True(t, ft.failed)
+}
+
+func TestHarness_StatusAndAttempts(t *testing.T) {
+	t.Parallel()
+	tries := 0
+	flaky := flow.Func("flaky", func(ctx context.Context) error {
+		tries++
+		if tries < 3 {
+			return errors.New("flake")
+		}
+		return nil
+	})
+	broken := flow.Func("broken", func(ctx context.Context) error { return errors.New("boom") })
+	after := flow.Func("after", func(ctx context.Context) error { return nil })
+	w := new(flow.Workflow)
+	w.Add(
+		flow.Step(flaky).Retry(func(ro *flow.RetryOption) {
+			ro.Attempts = 3
+			ro.Timer = zeroTimer{}
+		}),
+		flow.Step(after).DependsOn(broken),
+	)
+	h := flowtest.New(t, w)
+	err := h.Do(context.Background())
+	assert.Error(t, err)
+
+	h.AssertStatuses(map[flow.Steper]flow.StepStatus{
+		flaky:  flow.Succeeded,
+		broken: flow.Failed,
+		after:  flow.Skipped,
+	})
+	h.AssertAttempts(flaky, 3)
+	h.AssertAttempts(broken, 1)
+	h.AssertNotRan(after)
+	flowtest.AssertGolden(t, "status_and_attempts", flowtest.Snapshot(err))
+	assert.Equal(t, flowtest.Snapshot(err), h.Snapshot())
+}
+
+func TestHarness_SkippedInSubWorkflow(t *testing.T) {
+	t.Parallel()
+	ran := flow.Func("ran", func(ctx context.Context) error { return nil })
+	skipped := flow.Func("skipped", func(ctx context.Context) error { return nil })
+	inner := new(flow.Workflow)
+	inner.Add(
+		flow.Step(ran),
+		flow.Step(skipped).When(func(context.Context, map[flow.Steper]flow.StepResult) flow.StepStatus {
+			return flow.Skipped
+		}),
+	)
+	w := new(flow.Workflow)
+	w.Add(flow.Step(inner))
+	h := flowtest.New(t, w)
+	assert.NoError(t, h.Do(context.Background()))
+
+	h.AssertRan(ran)
+	h.AssertNotRan(skipped)
+	h.AssertStatus(skipped, flow.Skipped)
+	h.AssertAttempts(skipped, 0)
+}
+
+func TestHarness_MockClock(t *testing.T) {
+	t.Parallel()
+	slow := flow.Func("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	w := new(flow.Workflow)
+	w.Add(flow.Step(slow).Timeout(time.Hour))
+	h := flowtest.New(t, w)
+
+	done := make(chan error, 1)
+	go func() { done <- h.Do(context.Background()) }()
+	for {
+		// Wait for the step's deadline timer to be registered on the mock.
+		time.Sleep(time.Millisecond)
+		h.Clock.Add(time.Hour)
+		select {
+		case err := <-done:
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			h.AssertStatus(slow, flow.Canceled)
+			return
+		default:
+		}
+	}
+}
+
+func TestStub(t *testing.T) {
+	t.Parallel()
+	outerVM, innerVM := &createVM{}, &createVM{}
+	inner := new(flow.Workflow)
+	inner.Add(flow.Name(innerVM, "inner"))
+	w := new(flow.Workflow)
+	w.Add(
+		flow.Step(outerVM),
+		flow.Step(inner).DependsOn(outerVM),
+	)
+	n := flowtest.Stub(w, func(ctx context.Context, vm *createVM) error {
+		vm.ID = "vm-1"
+		return nil
+	})
+	assert.Equal(t, 2, n)
+
+	h := flowtest.New(t, w)
+	assert.NoError(t, h.Do(context.Background()))
+	assert.Equal(t, "vm-1", outerVM.ID)
+	assert.Equal(t, "vm-1", innerVM.ID)
+	h.AssertStatus(innerVM, flow.Succeeded)
+	h.AssertRanBefore(outerVM, innerVM)
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "<nil>\n", flowtest.Snapshot(nil))
+	assert.Equal(t, "boom\n", flowtest.Snapshot(errors.New("boom")))
+}
+
+// zeroTimer fires immediately so retry tests don't sleep for the backoff.
+type zeroTimer struct{ c chan time.Time }
+
+func (t zeroTimer) Start(time.Duration) {}
+func (t zeroTimer) Stop()               {}
+func (t zeroTimer) C() <-chan time.Time {
+	c := make(chan time.Time, 1)
+	c <- time.Now()
+	return c
+}
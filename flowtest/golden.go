@@ -0,0 +1,84 @@
+package flowtest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	flow "github.com/Azure/go-workflow"
+)
+
+// UpdateEnv is the environment variable that makes AssertGolden (re)write
+// golden files instead of comparing against them.
+const UpdateEnv = "FLOWTEST_UPDATE"
+
+// Snapshot renders err as stable text for golden comparisons.
+//
+// A flow.ErrWorkflow (found with errors.As) is rendered one Step per entry,
+// ordered by flow.String(step) rather than by finish time, so the output
+// does not depend on timing:
+//
+//	a: [Succeeded]
+//	b: [Failed]
+//	    boom
+//
+// nil renders as "<nil>"; any other error renders as err.Error().
+func Snapshot(err error) string {
+	if err == nil {
+		return "<nil>\n"
+	}
+	var errWorkflow flow.ErrWorkflow
+	if !errors.As(err, &errWorkflow) {
+		return err.Error() + "\n"
+	}
+	type entry struct{ name, result string }
+	entries := make([]entry, 0, len(errWorkflow))
+	for step, result := range errWorkflow {
+		entries = append(entries, entry{flow.String(step), result.Error()})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].name != entries[j].name {
+			return entries[i].name < entries[j].name
+		}
+		return entries[i].result < entries[j].result
+	})
+	var b strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&b, "%s: %s\n", e.name, e.result)
+	}
+	return b.String()
+}
+
+// AssertGolden compares got with the content of testdata/<name>.golden
+// (relative to the test's working directory). When the environment
+// variable FLOWTEST_UPDATE is non-empty, the golden file is written with
+// got instead and the assertion passes.
+func AssertGolden(t testing.TB, name, got string) bool {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("update golden file %s: %v", path, err)
+			return false
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Errorf("update golden file %s: %v", path, err)
+			return false
+		}
+		return true
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("read golden file %s: %v (run with %s=1 to create it)", path, err, UpdateEnv)
+		return false
+	}
+	if string(want) != got {
+		t.Errorf("%s mismatch (run with %s=1 to update)\n--- want\n%s\n+++ got\n%s", path, UpdateEnv, want, got)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,230 @@
+package flowtest
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/benbjohnson/clock"
+)
+
+// Harness wraps a Workflow under test. Build one with New, run the
+// Workflow with Harness.Do (or Workflow.Do directly), then use the Assert
+// helpers. Every Assert helper reports through the testing.TB passed to
+// New and returns whether the assertion held, like testify's assert.
+type Harness struct {
+	Workflow *flow.Workflow
+	// Clock is the mock clock installed as Workflow.Option.Clock. It is nil
+	// if the Workflow already had a Clock configured when New was called.
+	Clock *clock.Mock
+
+	t        testing.TB
+	mu       sync.Mutex
+	seq      int
+	runs     []*run
+	attempts map[flow.Steper]uint64
+}
+
+// run records one StepInterceptor invocation: the Step it saw and the
+// sequence numbers at which it started and finished (finished is 0 while
+// the Step is still running).
+type run struct {
+	step              flow.Steper
+	started, finished int
+}
+
+// New configures w for deterministic testing (see the package doc) and
+// returns a Harness recording its runs.
+func New(t testing.TB, w *flow.Workflow) *Harness {
+	h := &Harness{
+		Workflow: w,
+		t:        t,
+		attempts: make(map[flow.Steper]uint64),
+	}
+	sequential := true
+	w.Option.Sequential = &sequential
+	if w.Option.Clock == nil {
+		h.Clock = clock.NewMock()
+		w.Option.Clock = h.Clock
+	}
+	w.Option.StepInterceptors = append(w.Option.StepInterceptors, flow.StepInterceptorFunc(h.interceptStep))
+	w.Option.AttemptInterceptors = append(w.Option.AttemptInterceptors, flow.AttemptInterceptorFunc(h.interceptAttempt))
+	return h
+}
+
+// Do resets the recordings and the Workflow, then runs it.
+func (h *Harness) Do(ctx context.Context) error {
+	h.mu.Lock()
+	h.seq, h.runs = 0, nil
+	clear(h.attempts)
+	h.mu.Unlock()
+	if err := h.Workflow.Reset(); err != nil {
+		return err
+	}
+	return h.Workflow.Do(ctx)
+}
+
+func (h *Harness) interceptStep(ctx context.Context, step flow.Steper, next func(context.Context) error) error {
+	h.mu.Lock()
+	h.seq++
+	r := &run{step: step, started: h.seq}
+	h.runs = append(h.runs, r)
+	h.mu.Unlock()
+
+	err := next(ctx)
+
+	h.mu.Lock()
+	h.seq++
+	r.finished = h.seq
+	h.mu.Unlock()
+	return err
+}
+
+func (h *Harness) interceptAttempt(ctx context.Context, step flow.Steper, attempt uint64, next func(context.Context) error) error {
+	h.mu.Lock()
+	h.attempts[step]++
+	h.mu.Unlock()
+	return next(ctx)
+}
+
+// find returns the recorded run of step. An exact match wins; otherwise the
+// run of a recorded Step wrapping step (e.g. the NamedStep or MockStep) is
+// returned. The run of a sub-workflow doesn't count for the Steps in it,
+// which may not have run.
+func (h *Harness) find(step flow.Steper) *run {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var found *run
+	for _, r := range h.runs {
+		if r.step == step {
+			return r
+		}
+		if found == nil && matchLayer(r.step, is(step)) {
+			found = r
+		}
+	}
+	return found
+}
+
+// is returns a matcher for step itself.
+func is(step flow.Steper) func(flow.Steper) bool {
+	return func(s flow.Steper) bool { return s == step }
+}
+
+// Order returns the Steps in the order they started running. Steps of
+// sub-workflows appear after (and nested within) their parent Step.
+func (h *Harness) Order() []flow.Steper {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	order := make([]flow.Steper, 0, len(h.runs))
+	for _, r := range h.runs {
+		order = append(order, r.step)
+	}
+	return order
+}
+
+// Attempts returns how many attempts step made in the last run, counting
+// the first try. Steps that never ran report 0.
+func (h *Harness) Attempts(step flow.Steper) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if n, ok := h.attempts[step]; ok {
+		return n
+	}
+	for s, n := range h.attempts {
+		if matchLayer(s, is(step)) {
+			return n
+		}
+	}
+	return 0
+}
+
+// Ran reports whether step was dispatched to run (i.e. wasn't settled
+// inline by its Condition).
+func (h *Harness) Ran(step flow.Steper) bool { return h.find(step) != nil }
+
+// AssertRan asserts that step was dispatched to run.
+func (h *Harness) AssertRan(step flow.Steper) bool {
+	h.t.Helper()
+	if !h.Ran(step) {
+		h.t.Errorf("expected %s to run, but it didn't", flow.String(step))
+		return false
+	}
+	return true
+}
+
+// AssertNotRan asserts that step was never dispatched to run.
+func (h *Harness) AssertNotRan(step flow.Steper) bool {
+	h.t.Helper()
+	if h.Ran(step) {
+		h.t.Errorf("expected %s not to run, but it did", flow.String(step))
+		return false
+	}
+	return true
+}
+
+// AssertRanBefore asserts that a finished before b started. Both Steps
+// must have run.
+func (h *Harness) AssertRanBefore(a, b flow.Steper) bool {
+	h.t.Helper()
+	ra, rb := h.find(a), h.find(b)
+	switch {
+	case ra == nil:
+		h.t.Errorf("expected %s to run before %s, but %s didn't run", flow.String(a), flow.String(b), flow.String(a))
+		return false
+	case rb == nil:
+		h.t.Errorf("expected %s to run before %s, but %s didn't run", flow.String(a), flow.String(b), flow.String(b))
+		return false
+	case ra.finished == 0 || ra.finished > rb.started:
+		h.t.Errorf("expected %s to finish before %s started", flow.String(a), flow.String(b))
+		return false
+	}
+	return true
+}
+
+// AssertStatus asserts the current status of step, as recorded by the
+// Workflow (or the sub-workflow owning step).
+func (h *Harness) AssertStatus(step flow.Steper, want flow.StepStatus) bool {
+	h.t.Helper()
+	state := h.Workflow.StateOf(step)
+	if state == nil {
+		h.t.Errorf("step %s is not in the workflow", flow.String(step))
+		return false
+	}
+	if got := state.GetStatus(); got != want {
+		h.t.Errorf("expected %s to be %s, got %s", flow.String(step), want, got)
+		return false
+	}
+	return true
+}
+
+// AssertStatuses is AssertStatus for several Steps at once.
+func (h *Harness) AssertStatuses(want map[flow.Steper]flow.StepStatus) bool {
+	h.t.Helper()
+	ok := true
+	for step, status := range want {
+		ok = h.AssertStatus(step, status) && ok
+	}
+	return ok
+}
+
+// AssertAttempts asserts how many attempts step made, counting the first try.
+func (h *Harness) AssertAttempts(step flow.Steper, want uint64) bool {
+	h.t.Helper()
+	if got := h.Attempts(step); got != want {
+		h.t.Errorf("expected %s to make %d attempt(s), got %d", flow.String(step), want, got)
+		return false
+	}
+	return true
+}
+
+// Snapshot renders the current StepResult of every root Step in the
+// Workflow with Snapshot, whether or not Do returned an error.
+func (h *Harness) Snapshot() string {
+	results := make(flow.ErrWorkflow)
+	for _, step := range h.Workflow.Steps() {
+		results[step] = h.Workflow.StateOf(step).GetStepResult()
+	}
+	return Snapshot(results)
+}
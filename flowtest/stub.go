@@ -0,0 +1,61 @@
+package flowtest
+
+import (
+	"context"
+
+	flow "github.com/Azure/go-workflow"
+)
+
+// Stub replaces the Do of every Step of type T in w with do, using
+// flow.Mock under the hood: the original Step keeps its identity, name and
+// config, so As / HasStep / StateOf still find it. Sub-workflows (anything
+// embedding flow.Workflow) are searched recursively. It returns how many
+// Steps were stubbed.
+//
+//	flowtest.Stub(w, func(ctx context.Context, s *CreateVM) error {
+//	    s.ID = "vm-1"
+//	    return nil
+//	})
+//
+// Call Stub after the Workflow is fully built and before Do.
+func Stub[T flow.Steper](w *flow.Workflow, do func(context.Context, T) error) int {
+	return stub(w, do)
+}
+
+// workflowLike is satisfied by *flow.Workflow and by any type embedding it.
+type workflowLike interface {
+	Steps() []flow.Steper
+	Add(...flow.Builder) *flow.Workflow
+}
+
+func stub[T flow.Steper](w workflowLike, do func(context.Context, T) error) int {
+	n := 0
+	for _, root := range w.Steps() {
+		var (
+			typed T
+			found bool
+			sub   workflowLike
+		)
+		// Walk the root's Unwrap chain, stopping at the first match or at
+		// the first nested workflow, whose own roots are stubbed recursively.
+		flow.Traverse(root, func(s flow.Steper, _ []flow.Steper) flow.TraverseDecision {
+			if v, ok := s.(T); ok {
+				typed, found = v, true
+				return flow.TraverseStop
+			}
+			if v, ok := s.(workflowLike); ok {
+				sub = v
+				return flow.TraverseStop
+			}
+			return flow.TraverseContinue
+		})
+		switch {
+		case found:
+			w.Add(flow.Mock(root, func(ctx context.Context) error { return do(ctx, typed) }))
+			n++
+		case sub != nil:
+			n += stub(sub, do)
+		}
+	}
+	return n
+}
@@ -0,0 +1,264 @@
+// Package flowtiming analyzes where the time went in a finished Workflow
+// run: which chain of Steps determined the wall-clock time (the critical
+// path), how much each other Step could have been delayed without making
+// the run longer (slack), how long Steps waited for a concurrency lease,
+// ran, or backed off between retries, and how much parallelism the run
+// achieved.
+//
+//	err := w.Do(ctx)
+//	a, _ := flowtiming.Analyze(w)
+//	fmt.Println(a.Gantt(60))
+//
+// The analysis reads the timestamps the Workflow records in every Step's
+// flow.StepResult (ReadyAt, StartedAt, FinishedAt, Running), so it works
+// on any Workflow after Do returns, with no instrumentation installed
+// beforehand. It covers the root Steps of the Workflow passed in; analyze
+// a sub-workflow by passing it to Analyze on its own.
+//
+// To look at a run visually, install a TraceRecorder before Do and load the
+// file it saves in chrome://tracing or Perfetto: every Step, attempt and
+// backoff is a slice on the concurrency lane it ran on.
+package flowtiming
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	flow "github.com/Azure/go-workflow"
+)
+
+// ErrNotFinished is returned by Analyze for a Workflow that is running or
+// has Steps that haven't terminated.
+var ErrNotFinished = errors.New("flowtiming: workflow has not finished")
+
+// Analysis is the timing analysis of one Workflow run.
+type Analysis struct {
+	// Start is the earliest timestamp recorded by any Step, End the latest
+	// FinishedAt. Wall is End - Start.
+	Start, End time.Time
+	Wall       time.Duration
+	// Steps has one entry per root Step, ordered by when it started (Steps
+	// settled inline by their Condition sort by when they finished).
+	Steps []StepTiming
+	// CriticalPath is the chain of Steps, first to last, that determined
+	// End: each Step in it became ready when its predecessor finished.
+	CriticalPath []flow.Steper
+	// Busy is the sum of StepTiming.Active over all Steps.
+	Busy time.Duration
+	// Parallelism is Busy / Wall: the average number of Steps active at
+	// once. PeakParallelism is the most Steps ever active at once.
+	Parallelism     float64
+	PeakParallelism int
+}
+
+// StepTiming is the timing of one Step within a run.
+type StepTiming struct {
+	Step   flow.Steper
+	Status flow.StepStatus
+	// ReadyAt, StartedAt and FinishedAt are copied from the StepResult.
+	ReadyAt, StartedAt, FinishedAt time.Time
+	// Waiting is StartedAt - ReadyAt: time spent waiting for a concurrency
+	// lease after the Step was ready to run.
+	Waiting time.Duration
+	// Active is FinishedAt - StartedAt, split into Running (inside
+	// attempts) and BackingOff (between attempts).
+	Active, Running, BackingOff time.Duration
+	// Slack is how much later the Step could have finished without
+	// delaying End, given what its downstream Steps took. It is zero on
+	// the critical path.
+	Slack time.Duration
+	// Critical reports whether the Step is on the critical path.
+	Critical bool
+}
+
+// Ran reports whether the Step was dispatched to run, as opposed to being
+// settled inline by its Condition or never reached.
+func (st StepTiming) Ran() bool { return !st.StartedAt.IsZero() }
+
+// Timing returns the StepTiming of step (or of the root Step containing
+// it), and whether it was found.
+func (a *Analysis) Timing(step flow.Steper) (StepTiming, bool) {
+	for _, st := range a.Steps {
+		if st.Step == step {
+			return st, true
+		}
+	}
+	for _, st := range a.Steps {
+		if flow.HasStep(st.Step, step) {
+			return st, true
+		}
+	}
+	return StepTiming{}, false
+}
+
+// Analyze computes the timing Analysis of w's last run. It returns
+// ErrNotFinished unless every root Step has terminated.
+func Analyze(w *flow.Workflow) (*Analysis, error) {
+	if !w.IsTerminated() {
+		return nil, ErrNotFinished
+	}
+	a := &Analysis{}
+	index := make(map[flow.Steper]int)
+	for _, step := range w.Steps() {
+		result := w.StateOf(step).GetStepResult()
+		st := StepTiming{
+			Step:       step,
+			Status:     result.Status,
+			ReadyAt:    result.ReadyAt,
+			StartedAt:  result.StartedAt,
+			FinishedAt: result.FinishedAt,
+		}
+		if st.Ran() {
+			st.Waiting = nonNegative(st.StartedAt.Sub(st.ReadyAt))
+			st.Active = nonNegative(st.FinishedAt.Sub(st.StartedAt))
+			st.Running = min(result.Running, st.Active)
+			st.BackingOff = st.Active - st.Running
+		}
+		a.Steps = append(a.Steps, st)
+	}
+	slices.SortFunc(a.Steps, func(x, y StepTiming) int {
+		if c := x.sortKey().Compare(y.sortKey()); c != 0 {
+			return c
+		}
+		if c := x.FinishedAt.Compare(y.FinishedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(flow.String(x.Step), flow.String(y.Step))
+	})
+	for i, st := range a.Steps {
+		index[st.Step] = i
+		for _, t := range []time.Time{st.ReadyAt, st.StartedAt, st.FinishedAt} {
+			if !t.IsZero() && (a.Start.IsZero() || t.Before(a.Start)) {
+				a.Start = t
+			}
+		}
+		if st.FinishedAt.After(a.End) {
+			a.End = st.FinishedAt
+		}
+		a.Busy += st.Active
+	}
+	a.Wall = nonNegative(a.End.Sub(a.Start))
+	if a.Wall > 0 {
+		a.Parallelism = float64(a.Busy) / float64(a.Wall)
+	}
+	a.PeakParallelism = peak(a.Steps)
+
+	// Upstreams of each finished Step, normalized to root Steps.
+	ups := make(map[int][]int)
+	downs := make(map[int][]int)
+	for i, st := range a.Steps {
+		if st.FinishedAt.IsZero() {
+			continue
+		}
+		for up := range w.UpstreamOf(st.Step) {
+			if j, ok := index[up]; ok && !a.Steps[j].FinishedAt.IsZero() {
+				ups[i] = append(ups[i], j)
+				downs[j] = append(downs[j], i)
+			}
+		}
+	}
+	for _, js := range ups {
+		slices.Sort(js)
+	}
+	// depReady is when a Step's dependencies allowed it to start: the last
+	// upstream's finish, or Start for Steps without upstreams.
+	depReady := func(i int) time.Time {
+		ready := a.Start
+		for _, j := range ups[i] {
+			if f := a.Steps[j].FinishedAt; f.After(ready) {
+				ready = f
+			}
+		}
+		return ready
+	}
+
+	// Slack: the latest each Step could have finished without pushing any
+	// downstream past its own latest finish, and ultimately End.
+	latest := make(map[int]time.Time)
+	var latestFinish func(i int) time.Time
+	latestFinish = func(i int) time.Time {
+		if lf, ok := latest[i]; ok {
+			return lf
+		}
+		lf := a.End
+		for _, d := range downs[i] {
+			took := a.Steps[d].FinishedAt.Sub(depReady(d))
+			if l := latestFinish(d).Add(-took); l.Before(lf) {
+				lf = l
+			}
+		}
+		latest[i] = lf
+		return lf
+	}
+	last := -1
+	for i, st := range a.Steps {
+		if st.FinishedAt.IsZero() {
+			continue
+		}
+		a.Steps[i].Slack = nonNegative(latestFinish(i).Sub(st.FinishedAt))
+		if last < 0 || !st.FinishedAt.Before(a.Steps[last].FinishedAt) {
+			last = i
+		}
+	}
+
+	// Critical path: from the last Step to finish, repeatedly step back to
+	// the upstream that finished last, i.e. the one it was waiting for.
+	if last >= 0 {
+		var path []flow.Steper
+		for i, ok := last, true; ok; {
+			a.Steps[i].Critical = true
+			path = append(path, a.Steps[i].Step)
+			ok = false
+			var lastUp time.Time
+			for _, j := range ups[i] {
+				if f := a.Steps[j].FinishedAt; !ok || f.After(lastUp) {
+					i, lastUp, ok = j, f, true
+				}
+			}
+		}
+		slices.Reverse(path)
+		a.CriticalPath = path
+	}
+	return a, nil
+}
+
+// sortKey is when the Step appears on the timeline: when it started, or
+// when it was settled if it never ran.
+func (st StepTiming) sortKey() time.Time {
+	if st.Ran() {
+		return st.StartedAt
+	}
+	return st.FinishedAt
+}
+
+// peak returns the maximum number of Steps whose [StartedAt, FinishedAt)
+// intervals overlap.
+func peak(steps []StepTiming) int {
+	type event struct {
+		at    time.Time
+		delta int
+	}
+	var events []event
+	for _, st := range steps {
+		if st.Ran() && st.Active > 0 {
+			events = append(events, event{st.StartedAt, +1}, event{st.FinishedAt, -1})
+		}
+	}
+	// Ends sort before starts at the same instant: back-to-back Steps don't overlap.
+	slices.SortFunc(events, func(x, y event) int {
+		if c := x.at.Compare(y.at); c != 0 {
+			return c
+		}
+		return x.delta - y.delta
+	})
+	rv, cur := 0, 0
+	for _, e := range events {
+		cur += e.delta
+		rv = max(rv, cur)
+	}
+	return rv
+}
+
+func nonNegative(d time.Duration) time.Duration { return max(d, 0) }
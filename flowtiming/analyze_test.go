@@ -0,0 +1,154 @@
+package flowtiming_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/Azure/go-workflow/flowtiming"
+	"github.com/benbjohnson/clock"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// advanceTimer is a backoff.Timer that advances the mock clock by the
+// backoff instead of sleeping.
+type advanceTimer struct {
+	clock *clock.Mock
+	c     chan time.Time
+}
+
+func (t *advanceTimer) Start(d time.Duration) {
+	t.clock.Add(d)
+	t.c = make(chan time.Time, 1)
+	t.c <- t.clock.Now()
+}
+func (t *advanceTimer) Stop()               {}
+func (t *advanceTimer) C() <-chan time.Time { return t.c }
+
+// takes returns a Step named name that advances the mock clock by d, and
+// fails the first `fails` attempts.
+func takes(clk *clock.Mock, name string, d time.Duration, fails int) flow.Steper {
+	return flow.Func(name, func(ctx context.Context) error {
+		clk.Add(d)
+		if fails > 0 {
+			fails--
+			return errors.New("flaky")
+		}
+		return nil
+	})
+}
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+	clk := clock.NewMock()
+	var (
+		a       = takes(clk, "a", time.Second, 0)
+		b       = takes(clk, "b", 3*time.Second, 0)
+		c       = takes(clk, "c", time.Second, 1)
+		d       = takes(clk, "d", time.Second, 0)
+		skipped = takes(clk, "skipped", time.Second, 0)
+	)
+	sequential := true
+	w := new(flow.Workflow)
+	w.Option.Clock = clk
+	w.Option.Sequential = &sequential
+	w.Add(
+		flow.Steps(a, b),
+		flow.Steps(c).DependsOn(a).Retry(func(ro *flow.RetryOption) {
+			ro.Attempts = 2
+			ro.Backoff = backoff.NewConstantBackOff(500 * time.Millisecond)
+			ro.Timer = &advanceTimer{clock: clk}
+		}),
+		flow.Steps(d).DependsOn(a, b),
+		flow.Steps(skipped).DependsOn(d).When(flow.AnyFailed),
+	)
+
+	// One step at a time, dispatched by name:
+	//
+	//	a 0s-1s, b 1s-4s (waited 1s), c 4s-6.5s (waited 3s; 1s, backoff 0.5s, 1s), d 6.5s-7.5s (waited 2.5s),
+	//	then skipped is settled at 7.5s
+	require.NoError(t, w.Do(context.Background()))
+	analysis, err := flowtiming.Analyze(w)
+	require.NoError(t, err)
+
+	assert.Equal(t, 7500*time.Millisecond, analysis.Wall)
+	assert.Equal(t, 7500*time.Millisecond, analysis.Busy)
+	assert.InDelta(t, 1.0, analysis.Parallelism, 1e-9)
+	assert.Equal(t, 1, analysis.PeakParallelism)
+	assert.Equal(t, []flow.Steper{b, d, skipped}, analysis.CriticalPath)
+
+	timing := func(step flow.Steper) flowtiming.StepTiming {
+		st, ok := analysis.Timing(step)
+		require.True(t, ok)
+		return st
+	}
+	assert.Equal(t, time.Second, timing(b).Waiting)
+	assert.Equal(t, 3*time.Second, timing(b).Running)
+	assert.Equal(t, 3*time.Second, timing(c).Waiting)
+	assert.Equal(t, 2*time.Second, timing(c).Running)
+	assert.Equal(t, 500*time.Millisecond, timing(c).BackingOff)
+	assert.Equal(t, time.Second, timing(c).Slack) // nothing depends on c: it could end with the run
+	assert.Equal(t, time.Second, timing(a).Slack) // c could have started 1s later
+	assert.Zero(t, timing(b).Slack)
+	assert.True(t, timing(d).Critical)
+	assert.False(t, timing(skipped).Ran())
+	assert.Equal(t, flow.Skipped, timing(skipped).Status)
+
+	gantt := analysis.Gantt(15)
+	assert.Equal(t, strings.Join([]string{
+		"wall 7.5s, busy 7.5s, parallelism 1.00 (peak 1)",
+		"critical path: b -> d -> skipped",
+		"",
+		"  a       |##             | Succeeded wait 0s run 1s backoff 0s slack 1s",
+		"* b       |..######       | Succeeded wait 1s run 3s backoff 0s slack 0s",
+		"  c       |  ......#####  | Succeeded wait 3s run 2s backoff 500ms slack 1s",
+		"* d       |        .....##| Succeeded wait 2.5s run 1s backoff 0s slack 0s",
+		"* skipped |              -| Skipped slack 0s",
+		"",
+	}, "\n"), gantt)
+}
+
+func TestAnalyzeNotFinished(t *testing.T) {
+	t.Parallel()
+	w := new(flow.Workflow).Add(flow.Step(flow.NoOp("pending")))
+	_, err := flowtiming.Analyze(w)
+	assert.ErrorIs(t, err, flowtiming.ErrNotFinished)
+
+	empty, err := flowtiming.Analyze(new(flow.Workflow))
+	require.NoError(t, err)
+	assert.Empty(t, empty.CriticalPath)
+	assert.Contains(t, empty.Gantt(10), "wall 0s")
+}
+
+func TestAnalyzeConcurrent(t *testing.T) {
+	t.Parallel()
+	w := new(flow.Workflow)
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	block := func(name string) flow.Steper {
+		return flow.Func(name, func(ctx context.Context) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		})
+	}
+	x, y := block("x"), block("y")
+	w.Add(flow.Steps(x, y))
+	go func() {
+		<-started
+		<-started
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	require.NoError(t, w.Do(context.Background()))
+	analysis, err := flowtiming.Analyze(w)
+	require.NoError(t, err)
+	assert.Equal(t, 2, analysis.PeakParallelism)
+	assert.Greater(t, analysis.Parallelism, 1.5)
+	assert.Len(t, analysis.CriticalPath, 1)
+}
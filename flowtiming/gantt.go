@@ -0,0 +1,93 @@
+package flowtiming
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	flow "github.com/Azure/go-workflow"
+)
+
+// maxNameWidth caps the width of the Step name column in Gantt.
+const maxNameWidth = 40
+
+// Gantt renders the Analysis as a text Gantt chart with a bar of width
+// columns per Step, spanning Start to End:
+//
+//	wall 3s, busy 4s, parallelism 1.33 (peak 2)
+//	critical path: fetch -> build -> publish
+//
+//	  fetch   |#####               | Succeeded wait 0s run 1s backoff 0s slack 0s
+//	  lint    |     ##########     | Succeeded wait 0s run 2s backoff 0s slack 1s
+//	* build   |     ..########     | Succeeded wait 500ms run 1.5s backoff 0s slack 0s
+//	...
+//
+// '#' marks the time a Step was active (running or backing off), '.' the
+// time it waited for a concurrency lease and '-' the moment a Step was
+// settled without running; '*' flags the critical path.
+func (a *Analysis) Gantt(width int) string {
+	width = max(width, 1)
+	var b strings.Builder
+	fmt.Fprintf(&b, "wall %s, busy %s, parallelism %.2f (peak %d)\n", a.Wall, a.Busy, a.Parallelism, a.PeakParallelism)
+	critical := make([]string, 0, len(a.CriticalPath))
+	for _, step := range a.CriticalPath {
+		critical = append(critical, name(step))
+	}
+	fmt.Fprintf(&b, "critical path: %s\n\n", strings.Join(critical, " -> "))
+
+	nameWidth := 0
+	for _, st := range a.Steps {
+		nameWidth = max(nameWidth, len(name(st.Step)))
+	}
+	// pos maps t to a fractional column; a time span fills every column it
+	// overlaps, and at least one.
+	pos := func(t time.Time) float64 {
+		if a.Wall <= 0 {
+			return 0
+		}
+		return float64(t.Sub(a.Start)) / float64(a.Wall) * float64(width)
+	}
+	col := func(t time.Time) int { return min(int(pos(t)), width-1) }
+	fill := func(bar []byte, from, to time.Time, c byte) {
+		first, last := col(from), min(int(math.Ceil(pos(to)))-1, width-1)
+		for i := first; i <= max(first, last); i++ {
+			bar[i] = c
+		}
+	}
+	for _, st := range a.Steps {
+		bar := []byte(strings.Repeat(" ", width))
+		switch {
+		case st.Ran():
+			if st.Waiting > 0 {
+				fill(bar, st.ReadyAt, st.StartedAt, '.')
+			}
+			fill(bar, st.StartedAt, st.FinishedAt, '#')
+		case !st.FinishedAt.IsZero():
+			bar[col(st.FinishedAt)] = '-'
+		}
+		mark := ' '
+		if st.Critical {
+			mark = '*'
+		}
+		fmt.Fprintf(&b, "%c %-*s |%s| %s", mark, nameWidth, name(st.Step), bar, st.Status)
+		if st.Ran() {
+			fmt.Fprintf(&b, " wait %s run %s backoff %s", st.Waiting, st.Running, st.BackingOff)
+		}
+		if !st.FinishedAt.IsZero() {
+			fmt.Fprintf(&b, " slack %s", st.Slack)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// name renders step on a single line of at most maxNameWidth bytes.
+func name(step flow.Steper) string {
+	s, _, _ := strings.Cut(flow.String(step), "\n")
+	s = strings.TrimSuffix(s, " {")
+	if len(s) > maxNameWidth {
+		s = s[:maxNameWidth-3] + "..."
+	}
+	return s
+}
@@ -0,0 +1,249 @@
+package flowtiming
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/benbjohnson/clock"
+)
+
+// TraceRecorder records the Step and attempt boundaries of Workflow runs and
+// exports them in the Chrome Trace Event Format, which chrome://tracing and
+// https://ui.perfetto.dev load directly. No collector or network is needed:
+//
+//	rec := flowtiming.NewTraceRecorder()
+//	rec.Install(w)
+//	err := w.Do(ctx)
+//	_ = rec.Save("run.trace.json")
+//
+// Every Step is a slice on a concurrency lane (a track): a Step takes the
+// lowest lane free when it starts, so the number of lanes in use is the
+// parallelism at that moment. Within a Step's slice, each attempt is a
+// nested "attempt N" slice and the wait between two attempts a "backoff"
+// slice. Steps of a sub-workflow are nested in the slice of the
+// sub-workflow Step while they run one at a time, and spill over to other
+// lanes when they run concurrently.
+//
+// Steps settled inline by their Condition never reach the interceptor
+// chain and are not in the trace.
+type TraceRecorder struct {
+	// Clock timestamps the events. nil means the wall clock; set it to the
+	// Workflow's Option.Clock in tests.
+	Clock clock.Clock
+
+	mu     sync.Mutex
+	lanes  []bool // lanes[i] is true while a top-level slice is on lane i.
+	slices []slice
+}
+
+// NewTraceRecorder returns an empty TraceRecorder.
+func NewTraceRecorder() *TraceRecorder { return &TraceRecorder{} }
+
+// slice is one recorded duration event.
+type slice struct {
+	name, cat  string
+	lane       int
+	start, end time.Time
+	args       map[string]any
+}
+
+// span is the context value linking a running Step to its lane, so its
+// attempts and the Steps of a nested sub-workflow can find it.
+type span struct {
+	lane           int
+	hasChild       bool      // a nested Step currently occupies this span's lane.
+	lastAttemptEnd time.Time // end of the previous attempt, start of the backoff.
+}
+
+type spanKey struct{}
+
+// Install appends r to w.Option.StepInterceptors and
+// w.Option.AttemptInterceptors. Sub-workflows inherit both.
+func (r *TraceRecorder) Install(w *flow.Workflow) {
+	w.Option.StepInterceptors = append(w.Option.StepInterceptors, r)
+	w.Option.AttemptInterceptors = append(w.Option.AttemptInterceptors, r)
+}
+
+func (r *TraceRecorder) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock.Now()
+}
+
+// InterceptStep implements flow.StepInterceptor.
+func (r *TraceRecorder) InterceptStep(ctx context.Context, step flow.Steper, next func(context.Context) error) error {
+	parent, _ := ctx.Value(spanKey{}).(*span)
+	s := &span{lane: -1}
+	r.mu.Lock()
+	if parent != nil && !parent.hasChild {
+		parent.hasChild = true
+		s.lane = parent.lane
+	} else {
+		s.lane = slices.Index(r.lanes, false)
+		if s.lane < 0 {
+			s.lane = len(r.lanes)
+			r.lanes = append(r.lanes, false)
+		}
+		r.lanes[s.lane] = true
+	}
+	r.mu.Unlock()
+	start := r.now()
+
+	err := next(context.WithValue(ctx, spanKey{}, s))
+
+	end := r.now()
+	args := map[string]any{"status": string(flow.StatusOf(err))}
+	if err != nil {
+		args["error"] = err.Error()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if parent != nil && parent.lane == s.lane {
+		parent.hasChild = false
+	} else {
+		r.lanes[s.lane] = false
+	}
+	r.slices = append(r.slices, slice{name: name(step), cat: "step", lane: s.lane, start: start, end: end, args: args})
+	return err
+}
+
+// InterceptAttempt implements flow.AttemptInterceptor.
+func (r *TraceRecorder) InterceptAttempt(ctx context.Context, step flow.Steper, attempt uint64, next func(context.Context) error) error {
+	s, _ := ctx.Value(spanKey{}).(*span)
+	if s == nil { // the StepInterceptor is not installed
+		return next(ctx)
+	}
+	start := r.now()
+	if !s.lastAttemptEnd.IsZero() {
+		r.record(slice{name: "backoff", cat: "backoff", lane: s.lane, start: s.lastAttemptEnd, end: start})
+	}
+
+	err := next(ctx)
+
+	end := r.now()
+	s.lastAttemptEnd = end
+	args := map[string]any{"attempt": attempt}
+	if err != nil {
+		args["error"] = err.Error()
+	}
+	r.record(slice{name: fmt.Sprintf("attempt %d", attempt), cat: "attempt", lane: s.lane, start: start, end: end, args: args})
+	return err
+}
+
+func (r *TraceRecorder) record(s slice) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.slices = append(r.slices, s)
+}
+
+// TraceEvent is one event of the Chrome Trace Event Format. Timestamps and
+// durations are in microseconds since the first recorded event.
+type TraceEvent struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat,omitempty"`
+	Ph   string         `json:"ph"` // "X" for a complete (duration) event, "M" for metadata.
+	Ts   float64        `json:"ts"`
+	Dur  float64        `json:"dur,omitempty"`
+	Pid  int            `json:"pid"`
+	Tid  int            `json:"tid"` // the concurrency lane.
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// Events returns the recorded events: a thread_name metadata event per
+// lane, then the complete events of every step ("step"), attempt
+// ("attempt") and backoff ("backoff"), ordered by start time, outer slices
+// first.
+func (r *TraceRecorder) Events() []TraceEvent {
+	r.mu.Lock()
+	recorded := slices.Clone(r.slices)
+	lanes := len(r.lanes)
+	r.mu.Unlock()
+
+	// Outer slices first at the same start: a step before its first attempt.
+	depth := map[string]int{"step": 0, "backoff": 1, "attempt": 1}
+	slices.SortStableFunc(recorded, func(x, y slice) int {
+		if c := x.start.Compare(y.start); c != 0 {
+			return c
+		}
+		if c := y.end.Compare(x.end); c != 0 {
+			return c
+		}
+		return depth[x.cat] - depth[y.cat]
+	})
+	events := make([]TraceEvent, 0, lanes+len(recorded))
+	for lane := range lanes {
+		events = append(events, TraceEvent{
+			Name: "thread_name",
+			Ph:   "M",
+			Pid:  1,
+			Tid:  lane,
+			Args: map[string]any{"name": fmt.Sprintf("lane %d", lane)},
+		})
+	}
+	if len(recorded) == 0 {
+		return events
+	}
+	origin := recorded[0].start
+	micros := func(d time.Duration) float64 { return float64(d) / float64(time.Microsecond) }
+	for _, s := range recorded {
+		events = append(events, TraceEvent{
+			Name: s.name,
+			Cat:  s.cat,
+			Ph:   "X",
+			Ts:   micros(s.start.Sub(origin)),
+			Dur:  micros(s.end.Sub(s.start)),
+			Pid:  1,
+			Tid:  s.lane,
+			Args: s.args,
+		})
+	}
+	return events
+}
+
+// WriteTo writes the trace as a Chrome Trace Event Format JSON object.
+func (r *TraceRecorder) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.Marshal(struct {
+		TraceEvents     []TraceEvent `json:"traceEvents"`
+		DisplayTimeUnit string       `json:"displayTimeUnit"`
+	}{r.Events(), "ms"})
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// Save writes the trace to the file at path, replacing it.
+func (r *TraceRecorder) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := r.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// String renders the events one per line, for debugging and golden tests.
+func (r *TraceRecorder) String() string {
+	var b strings.Builder
+	for _, e := range r.Events() {
+		if e.Ph != "X" {
+			continue
+		}
+		fmt.Fprintf(&b, "lane %d %s+%s %s\n", e.Tid,
+			time.Duration(e.Ts*float64(time.Microsecond)), time.Duration(e.Dur*float64(time.Microsecond)), e.Name)
+	}
+	return b.String()
+}
@@ -0,0 +1,124 @@
+package flowtiming_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/Azure/go-workflow/flowtiming"
+	"github.com/benbjohnson/clock"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceRecorder(t *testing.T) {
+	t.Parallel()
+	clk := clock.NewMock()
+	var (
+		a     = takes(clk, "a", time.Second, 0)
+		flaky = takes(clk, "flaky", time.Second, 1)
+		inner = takes(clk, "inner", time.Second, 0)
+		last  = takes(clk, "last", time.Second, 0)
+	)
+	sub := new(flow.Workflow).Add(flow.Pipe(inner, last))
+	sequential := true
+	w := new(flow.Workflow)
+	w.Option.Clock = clk
+	w.Option.Sequential = &sequential
+	w.Add(
+		flow.Steps(a),
+		flow.Steps(flaky).DependsOn(a).Retry(func(ro *flow.RetryOption) {
+			ro.Attempts = 2
+			ro.Backoff = backoff.NewConstantBackOff(500 * time.Millisecond)
+			ro.Timer = &advanceTimer{clock: clk}
+		}),
+		flow.Name(sub, "sub"),
+		flow.Steps(sub).DependsOn(flaky),
+	)
+	rec := flowtiming.NewTraceRecorder()
+	rec.Clock = clk
+	rec.Install(w)
+	require.NoError(t, w.Do(context.Background()))
+
+	assert.Equal(t, strings.Join([]string{
+		"lane 0 0s+1s a",
+		"lane 0 0s+1s attempt 0",
+		"lane 0 1s+2.5s flaky",
+		"lane 0 1s+1s attempt 0",
+		"lane 0 2s+500ms backoff",
+		"lane 0 2.5s+1s attempt 1",
+		"lane 0 3.5s+2s sub",
+		"lane 0 3.5s+2s attempt 0",
+		"lane 0 3.5s+1s inner",
+		"lane 0 3.5s+1s attempt 0",
+		"lane 0 4.5s+1s last",
+		"lane 0 4.5s+1s attempt 0",
+		"",
+	}, "\n"), rec.String())
+
+	events := rec.Events()
+	require.NotEmpty(t, events)
+	assert.Equal(t, flowtiming.TraceEvent{
+		Name: "thread_name", Ph: "M", Pid: 1, Tid: 0,
+		Args: map[string]any{"name": "lane 0"},
+	}, events[0])
+	flakyEvent := events[3]
+	assert.Equal(t, "flaky", flakyEvent.Name)
+	assert.Equal(t, "X", flakyEvent.Ph)
+	assert.Equal(t, float64(time.Second/time.Microsecond), flakyEvent.Ts)
+	assert.Equal(t, float64(2500*time.Millisecond/time.Microsecond), flakyEvent.Dur)
+	assert.Equal(t, "Succeeded", flakyEvent.Args["status"])
+	assert.Equal(t, "flaky", events[4].Args["error"])
+
+	path := filepath.Join(t.TempDir(), "run.trace.json")
+	require.NoError(t, rec.Save(path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var trace struct {
+		TraceEvents []flowtiming.TraceEvent `json:"traceEvents"`
+	}
+	require.NoError(t, json.Unmarshal(data, &trace))
+	assert.Len(t, trace.TraceEvents, len(events))
+}
+
+func TestTraceRecorderLanes(t *testing.T) {
+	t.Parallel()
+	w := new(flow.Workflow)
+	release := make(chan struct{})
+	started := make(chan struct{}, 3)
+	block := func(name string) flow.Steper {
+		return flow.Func(name, func(ctx context.Context) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		})
+	}
+	x, y := block("x"), block("y")
+	// The inner steps of a sub-workflow running concurrently can't all nest
+	// in its slice: one does, the other spills to a new lane.
+	sub := new(flow.Workflow).Add(flow.Steps(y, block("z")))
+	w.Add(flow.Steps(x, sub))
+	go func() {
+		for range 3 {
+			<-started
+		}
+		close(release)
+	}()
+	rec := flowtiming.NewTraceRecorder()
+	rec.Install(w)
+	require.NoError(t, w.Do(context.Background()))
+
+	lanes := map[int]bool{}
+	for _, e := range rec.Events() {
+		if e.Ph == "X" {
+			lanes[e.Tid] = true
+		}
+	}
+	assert.Len(t, lanes, 3)
+}
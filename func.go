@@ -2,6 +2,7 @@ package flow
 
 import (
 	"context"
+	"encoding/json"
 )
 
 // Func adapts a `func(ctx) error` into a Step (no input, no output) named
@@ -54,6 +55,10 @@ type Function[I, O any] struct {
 }
 
 func (f *Function[I, O]) String() string { return f.Name }
+
+// GetOutput implements Outputer, see Output.
+func (f *Function[I, O]) GetOutput() O { return f.Output }
+
 func (f *Function[I, O]) Do(ctx context.Context) error {
 	var err error
 	if f.DoFunc != nil {
@@ -61,3 +66,30 @@ func (f *Function[I, O]) Do(ctx context.Context) error {
 	}
 	return err
 }
+
+// functionJSON is the serialized form of a Function: its name plus the
+// Input / Output values. DoFunc is behaviour, not state, and is omitted.
+type functionJSON[I, O any] struct {
+	Name   string `json:"name"`
+	Input  I      `json:"input"`
+	Output O      `json:"output"`
+}
+
+// MarshalJSON serializes the Function's Name, Input and Output, so a
+// Function's data can be persisted (e.g. by package flowrecord) even though
+// DoFunc cannot.
+func (f *Function[I, O]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(functionJSON[I, O]{Name: f.Name, Input: f.Input, Output: f.Output})
+}
+
+// UnmarshalJSON restores Input and Output from data produced by
+// MarshalJSON. Name and DoFunc are left untouched: they identify the
+// Function rather than describe one of its runs.
+func (f *Function[I, O]) UnmarshalJSON(data []byte) error {
+	v := functionJSON[I, O]{Input: f.Input, Output: f.Output}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	f.Input, f.Output = v.Input, v.Output
+	return nil
+}
@@ -0,0 +1,122 @@
+package flow
+
+import "context"
+
+// WorkflowHook is a set of callbacks run around a Workflow's Do, registered
+// in WorkflowOption.Hooks. Any callback may be nil.
+//
+//	w.Option.Hooks = append(w.Option.Hooks, flow.WorkflowHook{
+//	    OnStart: func(ctx context.Context, w *flow.Workflow) (context.Context, error) {
+//	        return ctx, lock.Acquire(ctx)
+//	    },
+//	    OnFailure: func(ctx context.Context, w *flow.Workflow, err error) error {
+//	        return fmt.Errorf("deploy failed: %w", err)
+//	    },
+//	    Finally: func(ctx context.Context, w *flow.Workflow, err error) error {
+//	        lock.Release()
+//	        return err
+//	    },
+//	})
+//
+// Order of a Do:
+//
+//  1. OnStart of every hook, in order, after the preflight check and before
+//     any Step is scheduled. Each may swap the context for the rest of the
+//     run. The first error aborts the run: no Step runs, the remaining
+//     OnStart and the OnSuccess / OnFailure callbacks are skipped, and the
+//     error goes to Finally.
+//  2. The Steps run.
+//  3. If the run succeeded, OnSuccess of every hook, until one returns an
+//     error, which fails the run. Otherwise OnFailure of every hook,
+//     receiving the ErrWorkflow (or what the previous OnFailure turned it
+//     into), until one swallows it by returning nil; each may wrap or
+//     replace the error.
+//  4. Finally of every hook, always (even if OnStart failed), with the
+//     current error; its return value is what the next Finally receives,
+//     and what Do returns.
+//
+// With Option.DontPanic, a panicking callback becomes an ErrPanic. Hooks
+// don't run for an empty Workflow nor when the preflight check fails with
+// ErrCycleDependency.
+//
+// Hooks are per Workflow: a sub-workflow runs the parent's hooks around its
+// own Do only if they have Inherit set (see Workflow.InheritOption).
+type WorkflowHook struct {
+	OnStart   func(context.Context, *Workflow) (context.Context, error)
+	OnSuccess func(context.Context, *Workflow) error
+	OnFailure func(context.Context, *Workflow, error) error
+	Finally   func(context.Context, *Workflow, error) error
+
+	// Inherit makes sub-workflows run this hook around their own Do too,
+	// e.g. for logging or metrics. Leave it unset for cleanup that must
+	// run once per run of this Workflow.
+	Inherit bool
+}
+
+// inheritedHooks returns the hooks of parent that sub-workflows inherit.
+func inheritedHooks(parent []WorkflowHook) []WorkflowHook {
+	var rv []WorkflowHook
+	for _, h := range parent {
+		if h.Inherit {
+			rv = append(rv, h)
+		}
+	}
+	return rv
+}
+
+// hookDo runs a hook callback, converting a panic into an error when
+// Option.DontPanic is set.
+func (w *Workflow) hookDo(fn func() error) error {
+	if w.dontPanic() {
+		return catchPanicAsError(fn)
+	}
+	return fn()
+}
+
+// hooksOnStart runs every OnStart callback; see WorkflowHook.
+func (w *Workflow) hooksOnStart(ctx context.Context) (context.Context, error) {
+	for _, h := range w.Option.Hooks {
+		if h.OnStart == nil {
+			continue
+		}
+		if err := w.hookDo(func() error {
+			ctxReturned, err := h.OnStart(ctx, w)
+			if ctxReturned != nil {
+				ctx = ctxReturned
+			}
+			return err
+		}); err != nil {
+			return ctx, err
+		}
+	}
+	return ctx, nil
+}
+
+// hooksOnEnd runs the OnSuccess callbacks if err is nil, or the OnFailure
+// callbacks otherwise; see WorkflowHook.
+func (w *Workflow) hooksOnEnd(ctx context.Context, err error) error {
+	succeeded := err == nil
+	for _, h := range w.Option.Hooks {
+		switch {
+		case succeeded && err != nil, !succeeded && err == nil:
+			return err
+		case succeeded && h.OnSuccess != nil:
+			err = w.hookDo(func() error { return h.OnSuccess(ctx, w) })
+		case !succeeded && h.OnFailure != nil:
+			prev := err
+			err = w.hookDo(func() error { return h.OnFailure(ctx, w, prev) })
+		}
+	}
+	return err
+}
+
+// hooksFinally runs every Finally callback, threading err through them.
+func (w *Workflow) hooksFinally(ctx context.Context, err error) error {
+	for _, h := range w.Option.Hooks {
+		if h.Finally != nil {
+			prev := err
+			err = w.hookDo(func() error { return h.Finally(ctx, w, prev) })
+		}
+	}
+	return err
+}
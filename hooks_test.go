@@ -0,0 +1,163 @@
+package flow_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordHook returns a WorkflowHook appending "<name>.<phase>" to events.
+func recordHook(name string, events *[]string) flow.WorkflowHook {
+	return flow.WorkflowHook{
+		OnStart: func(ctx context.Context, w *flow.Workflow) (context.Context, error) {
+			*events = append(*events, name+".OnStart")
+			return ctx, nil
+		},
+		OnSuccess: func(ctx context.Context, w *flow.Workflow) error {
+			*events = append(*events, name+".OnSuccess")
+			return nil
+		},
+		OnFailure: func(ctx context.Context, w *flow.Workflow, err error) error {
+			*events = append(*events, name+".OnFailure")
+			return err
+		},
+		Finally: func(ctx context.Context, w *flow.Workflow, err error) error {
+			*events = append(*events, name+".Finally")
+			return err
+		},
+	}
+}
+
+type hookCtxKey struct{}
+
+func TestWorkflowHooks(t *testing.T) {
+	t.Parallel()
+	boom := errors.New("boom")
+	t.Run("order around a successful run", func(t *testing.T) {
+		var events []string
+		w := new(flow.Workflow).Add(flow.Step(flow.Func("step", func(ctx context.Context) error {
+			events = append(events, "step")
+			return nil
+		})))
+		w.Option.Hooks = append(w.Option.Hooks, recordHook("a", &events), recordHook("b", &events))
+		require.NoError(t, w.Do(context.Background()))
+		assert.Equal(t, []string{
+			"a.OnStart", "b.OnStart",
+			"step",
+			"a.OnSuccess", "b.OnSuccess",
+			"a.Finally", "b.Finally",
+		}, events)
+	})
+	t.Run("order around a failed run", func(t *testing.T) {
+		var events []string
+		w := new(flow.Workflow).Add(flow.Step(flow.Func("step", func(ctx context.Context) error { return boom })))
+		w.Option.Hooks = append(w.Option.Hooks, recordHook("a", &events))
+		err := w.Do(context.Background())
+		assert.ErrorIs(t, err, boom)
+		assert.Equal(t, []string{"a.OnStart", "a.OnFailure", "a.Finally"}, events)
+	})
+	t.Run("OnStart error aborts the run, Finally still runs", func(t *testing.T) {
+		var events []string
+		step := flow.Func("step", func(ctx context.Context) error {
+			events = append(events, "step")
+			return nil
+		})
+		w := new(flow.Workflow).Add(flow.Step(step))
+		w.Option.Hooks = append(w.Option.Hooks,
+			flow.WorkflowHook{OnStart: func(ctx context.Context, w *flow.Workflow) (context.Context, error) {
+				return ctx, boom
+			}},
+			recordHook("a", &events),
+		)
+		err := w.Do(context.Background())
+		assert.ErrorIs(t, err, boom)
+		assert.Equal(t, []string{"a.Finally"}, events)
+		assert.Equal(t, flow.Pending, w.StateOf(step).GetStatus())
+	})
+	t.Run("OnStart context is visible to the steps", func(t *testing.T) {
+		var got any
+		w := new(flow.Workflow).Add(flow.Step(flow.Func("step", func(ctx context.Context) error {
+			got = ctx.Value(hookCtxKey{})
+			return nil
+		})))
+		w.Option.Hooks = append(w.Option.Hooks, flow.WorkflowHook{
+			OnStart: func(ctx context.Context, w *flow.Workflow) (context.Context, error) {
+				return context.WithValue(ctx, hookCtxKey{}, "lock"), nil
+			},
+		})
+		require.NoError(t, w.Do(context.Background()))
+		assert.Equal(t, "lock", got)
+	})
+	t.Run("OnFailure wraps or swallows the error", func(t *testing.T) {
+		var events []string
+		w := new(flow.Workflow).Add(flow.Step(flow.Func("step", func(ctx context.Context) error { return boom })))
+		w.Option.Hooks = append(w.Option.Hooks,
+			flow.WorkflowHook{OnFailure: func(ctx context.Context, w *flow.Workflow, err error) error {
+				var errWorkflow flow.ErrWorkflow
+				assert.ErrorAs(t, err, &errWorkflow)
+				return fmt.Errorf("deploy failed: %w", err)
+			}},
+			flow.WorkflowHook{Finally: func(ctx context.Context, w *flow.Workflow, err error) error {
+				assert.ErrorContains(t, err, "deploy failed")
+				return err
+			}},
+		)
+		err := w.Do(context.Background())
+		assert.ErrorIs(t, err, boom)
+		assert.ErrorContains(t, err, "deploy failed")
+
+		w.Option.Hooks = append([]flow.WorkflowHook{{
+			OnFailure: func(ctx context.Context, w *flow.Workflow, err error) error { return nil },
+		}}, recordHook("a", &events))
+		assert.NoError(t, w.Do(context.Background()))
+		assert.Equal(t, []string{"a.OnStart", "a.Finally"}, events)
+	})
+	t.Run("OnSuccess error fails the run", func(t *testing.T) {
+		var events []string
+		w := new(flow.Workflow).Add(flow.Step(flow.NoOp("step")))
+		w.Option.Hooks = append(w.Option.Hooks,
+			flow.WorkflowHook{OnSuccess: func(ctx context.Context, w *flow.Workflow) error { return boom }},
+			recordHook("a", &events),
+		)
+		assert.ErrorIs(t, w.Do(context.Background()), boom)
+		assert.Equal(t, []string{"a.OnStart", "a.Finally"}, events)
+	})
+	t.Run("Finally may replace the error", func(t *testing.T) {
+		w := new(flow.Workflow).Add(flow.Step(flow.Func("step", func(ctx context.Context) error { return boom })))
+		w.Option.Hooks = append(w.Option.Hooks, flow.WorkflowHook{
+			Finally: func(ctx context.Context, w *flow.Workflow, err error) error { return nil },
+		})
+		assert.NoError(t, w.Do(context.Background()))
+	})
+	t.Run("DontPanic recovers a panicking hook", func(t *testing.T) {
+		dontPanic := true
+		w := new(flow.Workflow).Add(flow.Step(flow.NoOp("step")))
+		w.Option.DontPanic = &dontPanic
+		w.Option.Hooks = append(w.Option.Hooks, flow.WorkflowHook{
+			OnSuccess: func(ctx context.Context, w *flow.Workflow) error { panic("oops") },
+		})
+		var errPanic flow.ErrPanic
+		assert.ErrorAs(t, w.Do(context.Background()), &errPanic)
+	})
+	t.Run("sub-workflows run only inherited hooks", func(t *testing.T) {
+		var events []string
+		inner := new(flow.Workflow).Add(flow.Step(flow.NoOp("step")))
+		w := new(flow.Workflow).Add(flow.Step(inner))
+		inherited := recordHook("inherited", &events)
+		inherited.Inherit = true
+		w.Option.Hooks = append(w.Option.Hooks, recordHook("once", &events), inherited)
+		require.NoError(t, w.Do(context.Background()))
+		assert.Equal(t, []string{
+			"once.OnStart", "inherited.OnStart",
+			"inherited.OnStart", "inherited.OnSuccess", "inherited.Finally", // inner
+			"once.OnSuccess", "inherited.OnSuccess",
+			"once.Finally", "inherited.Finally",
+		}, events)
+		assert.Empty(t, inner.Option.Hooks, "inherited hooks are restored after Do")
+	})
+}
@@ -0,0 +1,220 @@
+package flow
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// IdempotencyStore records which idempotency keys completed successfully,
+// with the state of the Step that completed them; see
+// AddSteps.IdempotencyKey. Implementations must be safe for concurrent use.
+type IdempotencyStore interface {
+	// Get returns the state recorded for key, and whether key completed.
+	Get(ctx context.Context, key string) (state []byte, done bool, err error)
+	// Put records that key completed successfully. state is the Step's
+	// JSON state, nil if it has none.
+	Put(ctx context.Context, key string, state []byte) error
+}
+
+// ErrIdempotent is wrapped with Succeed as the StepResult.Err of a Step
+// whose idempotency key had already completed, so Do was not invoked.
+type ErrIdempotent struct {
+	Key string
+}
+
+func (e ErrIdempotent) Error() string {
+	return fmt.Sprintf("idempotency key %q already completed", e.Key)
+}
+
+// idempotencyStore returns Option.IdempotencyStore, or the Workflow's own
+// in-memory store, kept across its runs.
+func (w *Workflow) idempotencyStore() IdempotencyStore {
+	if w.Option.IdempotencyStore != nil {
+		return w.Option.IdempotencyStore
+	}
+	w.idempotencyOnce.Do(func() { w.idempotency = NewMemoryIdempotencyStore() })
+	return w.idempotency
+}
+
+// executeIdempotent runs the step through executeWithRetry, unless its
+// idempotency key already completed; see AddSteps.IdempotencyKey.
+func (ex *stepExecution) executeIdempotent(ctx context.Context) error {
+	option := ex.state.Option()
+	if option == nil || option.IdempotencyKey == nil {
+		return ex.executeWithRetry(ctx)
+	}
+	key := option.IdempotencyKey(ctx, ex.step)
+	if key == "" {
+		return ex.executeWithRetry(ctx)
+	}
+	store := ex.w.idempotencyStore()
+	state, done, err := store.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("get idempotency key %q: %w", key, err)
+	}
+	if done {
+		if s := SerializableOf(ex.step); s != nil && state != nil {
+			if err := s.UnmarshalJSON(state); err != nil {
+				return fmt.Errorf("restore idempotency key %q: %w", key, err)
+			}
+		}
+		if err := ex.state.After(ctx, ex.step, nil); err != nil {
+			return err
+		}
+		return Succeed(ErrIdempotent{Key: key})
+	}
+
+	err = ex.executeWithRetry(ctx)
+	if StatusOf(err) != Succeeded {
+		return err
+	}
+	state = nil
+	if s := SerializableOf(ex.step); s != nil {
+		if state, err = s.MarshalJSON(); err != nil {
+			return fmt.Errorf("save idempotency key %q: %w", key, err)
+		}
+	}
+	if err := store.Put(ctx, key, state); err != nil {
+		return fmt.Errorf("put idempotency key %q: %w", key, err)
+	}
+	return nil
+}
+
+// Serializable is a Step layer whose state can be saved and restored as
+// JSON, e.g. *Function's Input and Output. It is the state kept for
+// idempotency keys, and recorded by package flowrecord.
+type Serializable interface {
+	json.Marshaler
+	json.Unmarshaler
+}
+
+// SerializableOf returns the first Serializable layer in step's Unwrap
+// chain, nil if none. Like Mutate, it doesn't descend into nested
+// workflows: their Steps have states of their own.
+func SerializableOf(step Steper) Serializable {
+	var found Serializable
+	Traverse(step, func(s Steper, _ []Steper) TraverseDecision {
+		if v, ok := s.(Serializable); ok {
+			found = v
+			return TraverseStop
+		}
+		if _, isWorkflow := s.(interface {
+			StateOf(Steper) *State
+		}); isWorkflow {
+			return TraverseEndBranch
+		}
+		return TraverseContinue
+	})
+	return found
+}
+
+// MemoryIdempotencyStore is an IdempotencyStore in memory: keys survive
+// re-runs within the process only.
+type MemoryIdempotencyStore struct {
+	mu   sync.Mutex
+	done map[string][]byte
+}
+
+// NewMemoryIdempotencyStore returns an empty MemoryIdempotencyStore.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{done: make(map[string][]byte)}
+}
+
+func (m *MemoryIdempotencyStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, done := m.done[key]
+	return state, done, nil
+}
+
+func (m *MemoryIdempotencyStore) Put(_ context.Context, key string, state []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.done[key] = state
+	return nil
+}
+
+// Delete forgets key, so its step runs again.
+func (m *MemoryIdempotencyStore) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.done, key)
+}
+
+// FileIdempotencyStore is an IdempotencyStore keeping one JSON file per
+// completed key in Dir, so keys survive process restarts. Files are named
+// after the SHA-256 of the key and written atomically.
+type FileIdempotencyStore struct {
+	Dir string
+}
+
+// NewFileIdempotencyStore returns a FileIdempotencyStore in dir, creating
+// it if needed.
+func NewFileIdempotencyStore(dir string) (*FileIdempotencyStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileIdempotencyStore{Dir: dir}, nil
+}
+
+// idempotencyFile is the content of a FileIdempotencyStore file.
+type idempotencyFile struct {
+	Key   string          `json:"key"`
+	State json.RawMessage `json:"state,omitempty"`
+}
+
+func (f *FileIdempotencyStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(f.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (f *FileIdempotencyStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	data, err := os.ReadFile(f.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var file idempotencyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, false, err
+	}
+	return file.State, true, nil
+}
+
+func (f *FileIdempotencyStore) Put(_ context.Context, key string, state []byte) error {
+	data, err := json.Marshal(idempotencyFile{Key: key, State: state})
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(f.Dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path(key))
+}
+
+// Delete forgets key, so its step runs again.
+func (f *FileIdempotencyStore) Delete(key string) error {
+	err := os.Remove(f.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
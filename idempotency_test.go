@@ -0,0 +1,135 @@
+package flow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingStore struct{ flow.MemoryIdempotencyStore }
+
+func (*failingStore) Put(context.Context, string, []byte) error { return errors.New("disk full") }
+
+func TestIdempotencyKey(t *testing.T) {
+	t.Parallel()
+	boom := errors.New("boom")
+	key := func(k string) func(context.Context, flow.Steper) string {
+		return func(context.Context, flow.Steper) string { return k }
+	}
+	t.Run("re-run after a partial failure skips completed keys", func(t *testing.T) {
+		creates := 0
+		createRG := flow.FuncO("create rg", func(ctx context.Context) (string, error) {
+			creates++
+			return "rg-1", nil
+		})
+		deployErr := boom
+		deploy := flow.Func("deploy", func(ctx context.Context) error { return deployErr })
+		var rg string
+		w := new(flow.Workflow).Add(
+			flow.Step(createRG).IdempotencyKey(key("create-rg/1")).
+				Output(func(ctx context.Context, f *flow.Function[struct{}, string]) error {
+					rg = f.Output
+					return nil
+				}),
+			flow.Step(deploy).DependsOn(createRG),
+		)
+		require.ErrorIs(t, w.Do(context.Background()), boom)
+		assert.Equal(t, 1, creates)
+
+		createRG.Output, rg, deployErr = "", "", nil
+		require.NoError(t, w.Do(context.Background()))
+		assert.Equal(t, 1, creates, "not created twice")
+		assert.Equal(t, "rg-1", createRG.Output, "output restored")
+		assert.Equal(t, "rg-1", rg, "Output callbacks run")
+		assert.Equal(t, flow.Succeeded, w.StateOf(createRG).GetStatus())
+		var idempotent flow.ErrIdempotent
+		require.ErrorAs(t, w.StateOf(createRG).GetError(), &idempotent)
+		assert.Equal(t, "create-rg/1", idempotent.Key)
+	})
+	t.Run("failures are not recorded", func(t *testing.T) {
+		calls := 0
+		notify := flow.Func("notify", func(ctx context.Context) error {
+			calls++
+			if calls == 1 {
+				return boom
+			}
+			return nil
+		})
+		w := new(flow.Workflow).Add(flow.Step(notify).IdempotencyKey(key("notify")))
+		assert.Error(t, w.Do(context.Background()))
+		assert.NoError(t, w.Do(context.Background()))
+		assert.NoError(t, w.Do(context.Background()))
+		assert.Equal(t, 2, calls)
+	})
+	t.Run("empty key opts out", func(t *testing.T) {
+		calls := 0
+		step := flow.Func("step", func(ctx context.Context) error {
+			calls++
+			return nil
+		})
+		w := new(flow.Workflow).Add(flow.Step(step).IdempotencyKey(key("")))
+		require.NoError(t, w.Do(context.Background()))
+		require.NoError(t, w.Do(context.Background()))
+		assert.Equal(t, 2, calls)
+	})
+	t.Run("file store survives across workflows", func(t *testing.T) {
+		store, err := flow.NewFileIdempotencyStore(t.TempDir())
+		require.NoError(t, err)
+		creates := 0
+		run := func() string {
+			createRG := flow.FuncO("create rg", func(ctx context.Context) (string, error) {
+				creates++
+				return "rg-1", nil
+			})
+			w := new(flow.Workflow).Add(flow.Step(createRG).IdempotencyKey(key("create-rg/1")))
+			w.Option.IdempotencyStore = store
+			require.NoError(t, w.Do(context.Background()))
+			return createRG.Output
+		}
+		assert.Equal(t, "rg-1", run())
+		assert.Equal(t, "rg-1", run())
+		assert.Equal(t, 1, creates)
+
+		require.NoError(t, store.Delete("create-rg/1"))
+		assert.Equal(t, "rg-1", run())
+		assert.Equal(t, 2, creates)
+	})
+	t.Run("sub-workflows inherit the store", func(t *testing.T) {
+		store := flow.NewMemoryIdempotencyStore()
+		calls := 0
+		step := flow.Func("step", func(ctx context.Context) error {
+			calls++
+			return nil
+		})
+		inner := new(flow.Workflow).Add(flow.Step(step).IdempotencyKey(key("step")))
+		w := new(flow.Workflow).Add(flow.Step(inner))
+		w.Option.IdempotencyStore = store
+		require.NoError(t, w.Do(context.Background()))
+		_, done, err := store.Get(context.Background(), "step")
+		require.NoError(t, err)
+		assert.True(t, done)
+
+		other := new(flow.Workflow).Add(flow.Step(step).IdempotencyKey(key("step")))
+		other.Option.IdempotencyStore = store
+		require.NoError(t, other.Do(context.Background()))
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("failing to record fails the step", func(t *testing.T) {
+		w := new(flow.Workflow).Add(flow.Step(flow.NoOp("step")).IdempotencyKey(key("step")))
+		w.Option.IdempotencyStore = &failingStore{}
+		assert.ErrorContains(t, w.Do(context.Background()), "disk full")
+	})
+}
+
+func TestSerializableOf(t *testing.T) {
+	t.Parallel()
+	f := flow.FuncO("f", func(context.Context) (int, error) { return 1, nil })
+	assert.Equal(t, flow.Serializable(f), flow.SerializableOf(&flow.NamedStep{Name: "named", Steper: f}))
+	assert.Nil(t, flow.SerializableOf(flow.NoOp("noop")))
+	sub := new(flow.Workflow).Add(flow.Step(f))
+	assert.Nil(t, flow.SerializableOf(sub), "doesn't descend into nested workflows")
+}
@@ -0,0 +1,74 @@
+package flow
+
+import "maps"
+
+// stepIndex locates the Steps of a Workflow without walking the Step trees.
+// addStep maintains it, so RootOf, StateOf and UpstreamOf cost O(1) for the
+// Steps known when their root was added.
+//
+// Composite Steps are assumed to keep the same Unwrap once added, except
+// sub-workflows (anything with a StateOf method, e.g. an embedded Workflow),
+// which may still grow or be reset: Steps inside them are confirmed, or
+// looked up, through the sub-workflow itself.
+type stepIndex struct {
+	roots  map[Steper]Steper // each Step in a root's tree when it was indexed → the root.
+	states map[Steper]*State // each Step in a root's tree, outside sub-workflows → the root's State.
+	open   Set[Steper]       // roots whose tree contains a sub-workflow.
+}
+
+// subWorkflow is implemented by Workflow and anything embedding it.
+type subWorkflow interface {
+	StateOf(Steper) *State
+	RootOf(Steper) Steper
+}
+
+// add indexes the tree of root, whose State is state. Entries of the roots
+// it absorbs are overwritten, as their trees are part of it.
+func (x *stepIndex) add(root Steper, state *State) {
+	if x.roots == nil {
+		x.roots = make(map[Steper]Steper)
+		x.states = make(map[Steper]*State)
+		x.open = make(Set[Steper])
+	}
+	Traverse(root, func(s Steper, walked []Steper) TraverseDecision {
+		x.roots[s] = root
+		return TraverseContinue
+	})
+	Traverse(root, func(s Steper, walked []Steper) TraverseDecision {
+		x.states[s] = state
+		if _, ok := s.(subWorkflow); ok {
+			x.open.Add(root)
+			return TraverseEndBranch
+		}
+		return TraverseContinue
+	})
+}
+
+// remove drops the entries of root, whose State is state, e.g. once it's
+// absorbed by a sub-workflow that add won't walk into.
+func (x *stepIndex) remove(root Steper, state *State) {
+	maps.DeleteFunc(x.roots, func(_, r Steper) bool { return r == root })
+	maps.DeleteFunc(x.states, func(_ Steper, s *State) bool { return s == state })
+	delete(x.open, root)
+}
+
+// contains reports whether the tree of root contains step, asking the
+// sub-workflows on the way rather than walking them.
+func contains(root, step Steper) bool {
+	found := false
+	Traverse(root, func(s Steper, walked []Steper) TraverseDecision {
+		if s == step {
+			found = true
+			return TraverseStop
+		}
+		if sub, ok := s.(subWorkflow); ok {
+			if sub.RootOf(step) != nil {
+				found = true
+				return TraverseStop
+			}
+			return TraverseEndBranch
+		}
+		return TraverseContinue
+	})
+	return found
+}
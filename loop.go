@@ -0,0 +1,167 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Loop builds a Step that runs the body sub-DAG repeatedly until a check
+// holds — the polling pattern, without abusing Retry with fake errors:
+//
+//	poll := flow.Loop(flow.Pipe(getVM, verifyVM)).
+//	    Until(func(ctx context.Context) (bool, error) {
+//	        return getVM.Output.State == "Provisioned", nil
+//	    }).
+//	    MaxIterations(10).
+//	    Interval(30 * time.Second)
+//	w.Add(flow.Step(poll).DependsOn(createVM))
+//
+// Each iteration Resets the owned sub-workflow and runs it again; the
+// iteration number (0-based) is available to the body's Steps through
+// LoopIterationFrom(ctx). The loop ends:
+//
+//   - successfully, when the Until check returns true after an iteration
+//     (or after MaxIterations iterations when no Until check is set);
+//   - with the body's error, as soon as an iteration fails;
+//   - with the check's error, if the Until check returns one;
+//   - with ErrLoopExhausted, when MaxIterations iterations ran and the
+//     Until check never held;
+//   - with the context's error, if ctx is done before an iteration starts
+//     or while waiting the Interval.
+//
+// A loop needs an Until check or MaxIterations, else Do fails with
+// ErrLoopUnbounded without running the body.
+//
+// LoopStep embeds Workflow, so the body's Steps are visible to Has / As /
+// HasStep / Mutate, and the parent's Option (Clock, interceptors, …) is
+// inherited like for any sub-workflow. The Interval is waited on the
+// inherited Option.Clock.
+func Loop(body ...Builder) *LoopStep {
+	l := &LoopStep{}
+	l.Add(body...)
+	return l
+}
+
+// LoopStep is the Step built by Loop. Configure it with Until,
+// MaxIterations and Interval; inspect what happened with Iterations.
+type LoopStep struct {
+	Workflow
+
+	until         func(context.Context) (bool, error)
+	maxIterations int
+	interval      time.Duration
+
+	mu         sync.Mutex
+	iterations []LoopIteration
+}
+
+// LoopIteration is the recorded outcome of one iteration of a LoopStep.
+type LoopIteration struct {
+	Index int
+	// Err is what the body's Workflow.Do returned: nil, or an ErrWorkflow.
+	Err error
+	// Results is the StepResult of every root Step of the body.
+	Results map[Steper]StepResult
+	// Done is the Until check's verdict (false if the iteration failed or
+	// the check was not run).
+	Done bool
+}
+
+// Until sets the check run after every successful iteration: the loop
+// ends when it returns true, or fails when it returns an error.
+func (l *LoopStep) Until(check func(context.Context) (bool, error)) *LoopStep {
+	l.until = check
+	return l
+}
+
+// MaxIterations caps the number of iterations. n <= 0 means no cap: the
+// loop runs until the Until check holds or ctx is done, so it needs one.
+func (l *LoopStep) MaxIterations(n int) *LoopStep {
+	l.maxIterations = n
+	return l
+}
+
+// Interval sets the delay between the end of an iteration and the start of
+// the next one.
+func (l *LoopStep) Interval(d time.Duration) *LoopStep {
+	l.interval = d
+	return l
+}
+
+// Iterations returns the iterations of the last (or current) Do, in order.
+func (l *LoopStep) Iterations() []LoopIteration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]LoopIteration(nil), l.iterations...)
+}
+
+// ErrLoopUnbounded is returned by a LoopStep with neither an Until check
+// nor MaxIterations, which would loop forever.
+var ErrLoopUnbounded = errors.New("loop has neither Until nor MaxIterations")
+
+// Do runs the loop; see Loop.
+func (l *LoopStep) Do(ctx context.Context) error {
+	l.mu.Lock()
+	l.iterations = nil
+	l.mu.Unlock()
+	if l.until == nil && l.maxIterations <= 0 {
+		return ErrLoopUnbounded
+	}
+	for i := 0; l.maxIterations <= 0 || i < l.maxIterations; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if i > 0 && l.interval > 0 {
+			timer := l.clock().Timer(l.interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		if err := l.Workflow.Reset(); err != nil {
+			return err
+		}
+		iterCtx := loopIterationKey.With(ctx, loopIteration(i))
+		iter := LoopIteration{Index: i, Err: l.Workflow.Do(iterCtx)}
+		iter.Results = make(map[Steper]StepResult)
+		for _, step := range l.Steps() {
+			iter.Results[step] = l.StateOf(step).GetStepResult()
+		}
+		var checkErr error
+		if iter.Err == nil && l.until != nil {
+			iter.Done, checkErr = l.until(iterCtx)
+		}
+		l.mu.Lock()
+		l.iterations = append(l.iterations, iter)
+		l.mu.Unlock()
+		switch {
+		case iter.Err != nil:
+			return iter.Err
+		case checkErr != nil:
+			return checkErr
+		case iter.Done:
+			return nil
+		}
+	}
+	if l.until == nil {
+		return nil
+	}
+	return ErrLoopExhausted{Iterations: l.maxIterations}
+}
+
+// loopIteration is the type of the iteration number stored in the body's
+// context; a dedicated type keeps the ContextKey unique.
+type loopIteration int
+
+var loopIterationKey = ContextKey[loopIteration]{}
+
+// LoopIterationFrom returns the 0-based iteration number of the innermost
+// LoopStep running ctx's Step, and whether there is one.
+func LoopIterationFrom(ctx context.Context) (int, bool) {
+	i, ok := loopIterationKey.From(ctx)
+	return int(i), ok
+}
@@ -0,0 +1,130 @@
+package flow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoop(t *testing.T) {
+	t.Parallel()
+	t.Run("poll until the check holds, waiting the interval on the workflow clock", func(t *testing.T) {
+		clk := clock.NewMock()
+		var seen []int
+		poll := flow.Func("poll", func(ctx context.Context) error {
+			i, ok := flow.LoopIterationFrom(ctx)
+			assert.True(t, ok)
+			seen = append(seen, i)
+			return nil
+		})
+		verify := flow.NoOp("verify")
+		loop := flow.Loop(flow.Pipe(poll, verify)).
+			Until(func(ctx context.Context) (bool, error) { return len(seen) == 3, nil }).
+			MaxIterations(5).
+			Interval(time.Minute)
+		w := new(flow.Workflow)
+		w.Option.Clock = clk
+		w.Add(flow.Step(loop))
+
+		done := make(chan error)
+		go func() { done <- w.Do(context.Background()) }()
+		for {
+			select {
+			case err := <-done:
+				require.NoError(t, err)
+				assert.Equal(t, []int{0, 1, 2}, seen)
+				iterations := loop.Iterations()
+				require.Len(t, iterations, 3)
+				for i, iter := range iterations {
+					assert.Equal(t, i, iter.Index)
+					assert.NoError(t, iter.Err)
+					assert.Equal(t, i == 2, iter.Done)
+					assert.Equal(t, flow.Succeeded, iter.Results[verify].Status)
+				}
+				assert.Equal(t, flow.Succeeded, w.StateOf(loop).GetStatus())
+				return
+			default:
+				clk.Add(time.Minute)
+			}
+		}
+	})
+	t.Run("exhausted", func(t *testing.T) {
+		loop := flow.Loop(flow.Step(flow.NoOp("poll"))).
+			Until(func(ctx context.Context) (bool, error) { return false, nil }).
+			MaxIterations(2)
+		err := loop.Do(context.Background())
+		var exhausted flow.ErrLoopExhausted
+		require.ErrorAs(t, err, &exhausted)
+		assert.Equal(t, 2, exhausted.Iterations)
+		assert.Len(t, loop.Iterations(), 2)
+	})
+	t.Run("a failing iteration ends the loop", func(t *testing.T) {
+		boom := errors.New("boom")
+		checked := false
+		loop := flow.Loop(flow.Step(flow.Func("poll", func(ctx context.Context) error { return boom }))).
+			Until(func(ctx context.Context) (bool, error) {
+				checked = true
+				return true, nil
+			})
+		err := loop.Do(context.Background())
+		assert.ErrorIs(t, err, boom)
+		assert.False(t, checked)
+		require.Len(t, loop.Iterations(), 1)
+		assert.ErrorIs(t, loop.Iterations()[0].Err, boom)
+	})
+	t.Run("check error", func(t *testing.T) {
+		boom := errors.New("boom")
+		loop := flow.Loop(flow.Step(flow.NoOp("poll"))).
+			Until(func(ctx context.Context) (bool, error) { return false, boom })
+		assert.ErrorIs(t, loop.Do(context.Background()), boom)
+	})
+	t.Run("without Until, runs MaxIterations times", func(t *testing.T) {
+		count := 0
+		loop := flow.Loop(flow.Step(flow.Func("count", func(ctx context.Context) error {
+			count++
+			return nil
+		}))).MaxIterations(3)
+		assert.NoError(t, loop.Do(context.Background()))
+		assert.Equal(t, 3, count)
+	})
+	t.Run("canceled while waiting the interval", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		loop := flow.Loop(flow.Step(flow.Func("poll", func(ctx context.Context) error {
+			cancel()
+			return nil
+		}))).Until(func(context.Context) (bool, error) { return false, nil }).Interval(time.Hour)
+		assert.ErrorIs(t, loop.Do(ctx), context.Canceled)
+		assert.Len(t, loop.Iterations(), 1)
+	})
+	t.Run("canceled between iterations", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		loop := flow.Loop(flow.Step(flow.Func("poll", func(ctx context.Context) error {
+			cancel()
+			return nil
+		}))).Until(func(context.Context) (bool, error) { return false, nil })
+		assert.ErrorIs(t, loop.Do(ctx), context.Canceled)
+		assert.Len(t, loop.Iterations(), 1)
+	})
+	t.Run("unbounded", func(t *testing.T) {
+		ran := false
+		loop := flow.Loop(flow.Step(flow.Func("poll", func(ctx context.Context) error {
+			ran = true
+			return nil
+		})))
+		assert.ErrorIs(t, loop.Do(context.Background()), flow.ErrLoopUnbounded)
+		assert.False(t, ran)
+	})
+	t.Run("body steps are visible through the loop", func(t *testing.T) {
+		poll := flow.NoOp("poll")
+		loop := flow.Loop(flow.Step(poll))
+		assert.True(t, flow.HasStep(loop, poll))
+		_, ok := flow.LoopIterationFrom(context.Background())
+		assert.False(t, ok)
+	})
+}
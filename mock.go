@@ -30,3 +30,7 @@ type MockStep struct {
 
 func (m *MockStep) Unwrap() Steper               { return m.Step }
 func (m *MockStep) Do(ctx context.Context) error { return m.MockDo(ctx) }
+
+// String renders the mocked Step, so a mock shows up in logs and ErrWorkflow
+// under the original Step's name.
+func (m *MockStep) String() string { return String(m.Step) }
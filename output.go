@@ -0,0 +1,78 @@
+package flow
+
+import "reflect"
+
+// Outputer is implemented by Steps producing an output of type O, like
+// Function. See Output.
+type Outputer[O any] interface {
+	Steper
+	GetOutput() O
+}
+
+// Output reads the output of type O of step after a run of w:
+//
+//	w.Add(flow.Step(fetch))
+//	_ = w.Do(ctx)
+//	body, result, err := flow.Output[[]byte](w, fetch)
+//
+// During a run, only read it once step terminated, e.g. from a Step, or an
+// Input callback, downstream of step: the output isn't synchronized, so
+// reading it while step runs is a data race.
+//
+// step may also wrap the Outputer (the first one in pre-order is read, see
+// As), or sit in a sub-workflow of w, e.g. a step found with As[T].
+//
+// result is the StepResult of step in w. err is nil only if step succeeded:
+// otherwise it is the StepResult itself, wrapping the step's error, or an
+// ErrNoOutput if step isn't in w or doesn't produce an O. The output is
+// returned whatever the status, as the step left it.
+func Output[O any](w *Workflow, step Steper) (O, StepResult, error) {
+	var output O
+	state := w.StateOf(step)
+	outputers := As[Outputer[O]](step)
+	if state == nil || len(outputers) == 0 {
+		return output, StepResult{}, ErrNoOutput{Step: step, Type: reflect.TypeFor[O]()}
+	}
+	output = outputers[0].GetOutput()
+	result := state.GetStepResult()
+	if result.Status != Succeeded {
+		return output, result, result
+	}
+	return output, result, nil
+}
+
+// Handle is a typed reference to a Step added to a Workflow by Add.
+type Handle[O any] struct {
+	w    *Workflow
+	step Outputer[O]
+}
+
+// Add adds step to w, like w.Add(Step(step)), and returns a Handle to read
+// its output once w ran:
+//
+//	h := flow.Add(w, flow.FuncO("version", getVersion))
+//	w.Add(flow.Step(deploy).DependsOn(h.Step()))
+//	_ = w.Do(ctx)
+//	version, err := h.Result()
+//
+// Configure the step as usual with w.Add(Step(h.Step())...).
+func Add[O any](w *Workflow, step Outputer[O]) Handle[O] {
+	w.Add(Step(step))
+	return Handle[O]{w: w, step: step}
+}
+
+// Step returns the Step the Handle refers to.
+func (h Handle[O]) Step() Outputer[O] { return h.step }
+
+// Result returns the output of the Step, and an error unless the Step
+// succeeded; see Output.
+func (h Handle[O]) Result() (O, error) {
+	output, _, err := Output[O](h.w, h.step)
+	return output, err
+}
+
+// StepResult returns the StepResult of the Step in the Workflow.
+func (h Handle[O]) StepResult() StepResult {
+	_, result, _ := Output[O](h.w, h.step)
+	return result
+}
@@ -0,0 +1,81 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutput(t *testing.T) {
+	t.Parallel()
+	t.Run("Add returns a typed handle", func(t *testing.T) {
+		w := new(Workflow)
+		h := Add(w, FuncO("answer", func(context.Context) (int, error) { return 42, nil }))
+		out, err := h.Result()
+		assert.Error(t, err, "not run yet")
+		assert.Zero(t, out)
+		assert.Equal(t, Pending, h.StepResult().Status)
+
+		assert.NoError(t, w.Do(context.Background()))
+		out, err = h.Result()
+		assert.NoError(t, err)
+		assert.Equal(t, 42, out)
+		assert.Equal(t, Succeeded, h.StepResult().Status)
+	})
+	t.Run("failed step", func(t *testing.T) {
+		w := new(Workflow)
+		boom := errors.New("boom")
+		h := Add(w, FuncO("fail", func(context.Context) (string, error) { return "partial", boom }))
+		assert.Error(t, w.Do(context.Background()))
+		out, err := h.Result()
+		assert.ErrorIs(t, err, boom)
+		assert.Equal(t, "partial", out, "the output is returned as the step left it")
+		var result StepResult
+		if assert.ErrorAs(t, err, &result) {
+			assert.Equal(t, Failed, result.Status)
+		}
+	})
+	t.Run("configured with Step", func(t *testing.T) {
+		w := new(Workflow)
+		up := Add(w, FuncO("up", func(context.Context) (int, error) { return 1, nil }))
+		f := FuncIO("down", func(_ context.Context, in int) (int, error) { return in + 1, nil })
+		down := Add(w, f)
+		w.Add(Step(f).DependsOn(up.Step()).Input(func(_ context.Context, f *Function[int, int]) error {
+			f.Input, _ = up.Result()
+			return nil
+		}))
+		assert.NoError(t, w.Do(context.Background()))
+		out, err := down.Result()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, out)
+	})
+	t.Run("wrapped and nested steps", func(t *testing.T) {
+		f := FuncO("inner", func(context.Context) (string, error) { return "hi", nil })
+		inner := new(Workflow).Add(Step(&NamedStep{Name: "named", Steper: f}))
+		w := new(Workflow).Add(Step(inner))
+		assert.NoError(t, w.Do(context.Background()))
+
+		found := As[*Function[struct{}, string]](w)
+		if assert.Len(t, found, 1) {
+			out, result, err := Output[string](w, found[0])
+			assert.NoError(t, err)
+			assert.Equal(t, "hi", out)
+			assert.Equal(t, Succeeded, result.Status)
+		}
+		named := As[*NamedStep](w)[0]
+		out, _, err := Output[string](w, named)
+		assert.NoError(t, err)
+		assert.Equal(t, "hi", out)
+	})
+	t.Run("no output", func(t *testing.T) {
+		f := FuncO("answer", func(context.Context) (int, error) { return 42, nil })
+		w := new(Workflow).Add(Step(f))
+		_, _, err := Output[string](w, f)
+		assert.ErrorAs(t, err, new(ErrNoOutput))
+		_, _, err = Output[int](w, NoOp("other"))
+		assert.ErrorAs(t, err, new(ErrNoOutput))
+		assert.EqualError(t, err, "no output of type int for step other in the workflow")
+	})
+}
@@ -0,0 +1,131 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// Race builds a Step that runs steps in parallel and succeeds as soon as
+// one of them succeeds; the others are canceled through their context.
+//
+//	w.Add(flow.Step(flow.Race(fromPrimary, fromMirror)).DependsOn(resolve))
+//
+// It is Quorum(1, steps...).
+func Race(steps ...Steper) *ParallelStep { return Quorum(1, steps...) }
+
+// Quorum builds a Step that runs steps in parallel and succeeds as soon as
+// n of them succeed; the rest are canceled through their context. It fails
+// with ErrQuorum once n successes are out of reach, canceling what is
+// still running.
+//
+//	deploy := flow.Quorum(3, eastus, westus, northeu, westeu, japan)
+func Quorum(n int, steps ...Steper) *ParallelStep {
+	p := &ParallelStep{Quorum: n}
+	p.Add(Steps(steps...))
+	return p
+}
+
+// AllSettled builds a Step that runs steps in parallel, waits for all of
+// them to terminate, and succeeds whatever their outcome. If some children
+// didn't succeed, it returns their ErrWorkflow wrapped in an ErrSucceed; read
+// each child's outcome with ParallelStep.Results.
+func AllSettled(steps ...Steper) *ParallelStep { return Quorum(0, steps...) }
+
+// ParallelStep is the Step built by Race, Quorum and AllSettled.
+//
+// It embeds Workflow, each child being an independent root Step of it, so
+// Has / As / HasStep see inside, the parent's Option (interceptors,
+// Mutators, Clock, …) is inherited, and children keep their own retry /
+// timeout configuration: add it with p.Add(flow.Step(child).Retry(...)).
+// FailFast is not inherited: the children fail independently, and only the
+// ParallelStep's own outcome counts for a FailFast parent.
+//
+// A zero ParallelStep with its Quorum set, and children added, is ready to
+// use.
+type ParallelStep struct {
+	Workflow
+	// Quorum is how many children must succeed; 0 means: wait for every
+	// child and never fail.
+	Quorum int
+
+	mu                  sync.Mutex
+	succeeded, finished int
+	cancel              context.CancelCauseFunc
+}
+
+// ErrQuorumReached is the context cancellation cause of the children still
+// running when a ParallelStep's Quorum was reached.
+var ErrQuorumReached = errors.New("quorum reached")
+
+// Results returns the StepResult of every child in the last Do.
+func (p *ParallelStep) Results() ErrWorkflow {
+	results := make(ErrWorkflow)
+	for _, step := range p.Steps() {
+		results[step] = p.StateOf(step).GetStepResult()
+	}
+	return results
+}
+
+// Do runs the children; see Race, Quorum and AllSettled.
+func (p *ParallelStep) Do(ctx context.Context) error {
+	ctxChildren, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	p.mu.Lock()
+	p.succeeded, p.finished, p.cancel = 0, 0, cancel
+	p.mu.Unlock()
+
+	// One child's failure must not cancel the others, whatever the parent's
+	// FailFast; settle cancels them once the outcome is known.
+	prev := p.Option
+	defer func() { p.Option = prev }()
+	failFast := false
+	p.Option.FailFast = &failFast
+	p.Option.StepInterceptors = append(slices.Clip(p.Option.StepInterceptors), StepInterceptorFunc(p.settle))
+
+	err := p.Workflow.Do(ctxChildren)
+
+	results := p.Results()
+	if p.Quorum <= 0 {
+		if err != nil {
+			return Succeed(err)
+		}
+		return nil
+	}
+	succeeded := 0
+	for _, result := range results {
+		if result.Status == Succeeded {
+			succeeded++
+		}
+	}
+	if succeeded >= p.Quorum {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return ErrQuorum{Want: p.Quorum, Succeeded: succeeded, Results: results}
+}
+
+// settle is the StepInterceptor counting the children's outcomes, and
+// canceling the others once the Quorum is reached or out of reach.
+func (p *ParallelStep) settle(ctx context.Context, step Steper, next func(context.Context) error) error {
+	err := next(ctx)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, isChild := p.steps[step]; !isChild || p.Quorum <= 0 || p.cancel == nil {
+		return err // steps of nested workflows inherit the interceptor
+	}
+	p.finished++
+	if StatusOf(err) == Succeeded {
+		p.succeeded++
+	}
+	switch {
+	case p.succeeded >= p.Quorum:
+		p.cancel(ErrQuorumReached)
+	case p.succeeded+len(p.Steps())-p.finished < p.Quorum:
+		p.cancel(ErrQuorum{Want: p.Quorum, Succeeded: p.succeeded})
+	}
+	return err
+}
@@ -0,0 +1,146 @@
+package flow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blocked returns a Step that waits for its context to be done and returns
+// ctx.Err().
+func blocked(name string) flow.Steper {
+	return flow.Func(name, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+}
+
+func TestRace(t *testing.T) {
+	t.Parallel()
+	fast := flow.Func("fast", func(ctx context.Context) error { return nil })
+	slow := blocked("slow")
+	race := flow.Race(fast, slow)
+	w := new(flow.Workflow).Add(flow.Step(race))
+
+	require.NoError(t, w.Do(context.Background()))
+	results := race.Results()
+	assert.Equal(t, flow.Succeeded, results[fast].Status)
+	assert.Equal(t, flow.Canceled, results[slow].Status)
+	assert.True(t, flow.HasStep(race, slow))
+	assert.Equal(t, flow.Succeeded, w.StateOf(race).GetStatus())
+}
+
+func TestQuorum(t *testing.T) {
+	t.Parallel()
+	boom := errors.New("boom")
+	ok := func(name string) flow.Steper { return flow.Func(name, func(ctx context.Context) error { return nil }) }
+	fail := func(name string) flow.Steper { return flow.Func(name, func(ctx context.Context) error { return boom }) }
+
+	t.Run("reached", func(t *testing.T) {
+		quorum := flow.Quorum(2, ok("east"), fail("west"), ok("north"), blocked("south"))
+		require.NoError(t, quorum.Do(context.Background()))
+	})
+	t.Run("out of reach cancels the rest", func(t *testing.T) {
+		south := blocked("south")
+		quorum := flow.Quorum(3, ok("east"), fail("west"), fail("north"), south)
+		w := new(flow.Workflow).Add(flow.Step(quorum))
+		err := w.Do(context.Background())
+		require.Error(t, err)
+		assert.Equal(t, flow.Failed, w.StateOf(quorum).GetStatus())
+
+		var errQuorum flow.ErrQuorum
+		require.ErrorAs(t, err, &errQuorum)
+		assert.Equal(t, 3, errQuorum.Want)
+		assert.Equal(t, 1, errQuorum.Succeeded)
+		assert.Len(t, errQuorum.Results, 4)
+		assert.Equal(t, flow.Canceled, errQuorum.Results[south].Status)
+		assert.Contains(t, err.Error(), "quorum not reached: 1 step(s) succeeded, want 3")
+		assert.Contains(t, err.Error(), "boom")
+	})
+	t.Run("children keep their own options", func(t *testing.T) {
+		attempts := 0
+		flaky := flow.Func("flaky", func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return boom
+			}
+			return nil
+		})
+		quorum := flow.Quorum(1, flaky)
+		quorum.Add(flow.Step(flaky).Retry(func(ro *flow.RetryOption) {
+			ro.Attempts = 3
+			ro.Backoff = nil
+			ro.NextBackOff = func(context.Context, flow.RetryEvent, time.Duration) time.Duration { return 0 }
+		}))
+		require.NoError(t, quorum.Do(context.Background()))
+		assert.Equal(t, 3, attempts)
+	})
+	t.Run("nested workflows don't count", func(t *testing.T) {
+		inner := new(flow.Workflow).Add(flow.Steps(ok("a"), ok("b")))
+		// Counting a and b would reach 3; only inner counts, so 3 is out of
+		// reach as soon as inner succeeds, which cancels other.
+		quorum := flow.Quorum(3, inner, blocked("other"))
+		var errQuorum flow.ErrQuorum
+		require.ErrorAs(t, quorum.Do(context.Background()), &errQuorum)
+		assert.Equal(t, 1, errQuorum.Succeeded)
+	})
+	t.Run("a failing child doesn't cancel the others under a FailFast parent", func(t *testing.T) {
+		failed := make(chan struct{})
+		afterFail := func(name string) flow.Steper {
+			return flow.Func(name, func(ctx context.Context) error {
+				<-failed
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(50 * time.Millisecond):
+					return nil
+				}
+			})
+		}
+		west := flow.Func("west", func(ctx context.Context) error {
+			defer close(failed)
+			return boom
+		})
+		quorum := flow.Quorum(2, afterFail("east"), afterFail("north"), west)
+		failFast := true
+		w := &flow.Workflow{Option: flow.WorkflowOption{FailFast: &failFast}}
+		w.Add(flow.Step(quorum))
+		require.NoError(t, w.Do(context.Background()))
+		assert.Equal(t, &failFast, w.Option.FailFast)
+		assert.Nil(t, quorum.Option.FailFast, "restored after Do")
+	})
+	t.Run("zero value", func(t *testing.T) {
+		fast := ok("fast")
+		slow := blocked("slow")
+		quorum := &flow.ParallelStep{Quorum: 1}
+		quorum.Add(flow.Steps(fast, slow))
+		require.NoError(t, quorum.Do(context.Background()))
+		assert.Equal(t, flow.Canceled, quorum.Results()[slow].Status)
+		assert.Empty(t, quorum.Option.StepInterceptors, "restored after Do")
+	})
+}
+
+func TestAllSettled(t *testing.T) {
+	t.Parallel()
+	boom := errors.New("boom")
+	good := flow.Func("good", func(ctx context.Context) error { return nil })
+	bad := flow.Func("bad", func(ctx context.Context) error { return boom })
+	settled := flow.AllSettled(good, bad)
+	err := settled.Do(context.Background())
+	assert.Equal(t, flow.Succeeded, flow.StatusOf(err))
+	var errWorkflow flow.ErrWorkflow
+	require.ErrorAs(t, err, &errWorkflow)
+	assert.ErrorIs(t, errWorkflow[bad].Err, boom)
+	results := settled.Results()
+	assert.Equal(t, flow.Succeeded, results[good].Status)
+	assert.Equal(t, flow.Failed, results[bad].Status)
+	assert.ErrorIs(t, results[bad].Err, boom)
+
+	w := new(flow.Workflow).Add(flow.Step(flow.AllSettled(good)))
+	assert.NoError(t, w.Do(context.Background()), "all succeeded")
+}
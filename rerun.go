@@ -0,0 +1,60 @@
+package flow
+
+import (
+	"context"
+	"maps"
+)
+
+// RetryFailed runs the Workflow again, only for what didn't succeed last
+// time: Failed and Canceled root steps, the steps Skipped downstream of them,
+// and steps that never ran. Every other step keeps its result from the
+// previous run and doesn't run again, so e.g. the Output of a succeeded
+// Function stays available to its downstreams.
+//
+//	if err := w.Do(ctx); err != nil {
+//	    err = w.RetryFailed(ctx) // after fixing the flaky dependency
+//	}
+//
+// A retried sub-workflow runs again in full. RetryFailed returns
+// ErrWorkflowIsRunning if a Do call is in flight; on a Workflow that never
+// ran, it is the same as Do.
+func (w *Workflow) RetryFailed(ctx context.Context) error {
+	if !w.isRunning.TryLock() {
+		return ErrWorkflowIsRunning
+	}
+	defer w.isRunning.Unlock()
+
+	p := w.topology()
+	retry := make(Set[Steper])
+	var queue []Steper
+	for _, step := range w.order {
+		switch w.steps[step].GetStatus() {
+		case Failed, Canceled, Pending, Running:
+			retry.Add(step)
+			queue = append(queue, step)
+		}
+	}
+	for len(queue) > 0 {
+		step := queue[0]
+		queue = queue[1:]
+		for _, down := range p.downs[step] {
+			if !retry.Has(down) && w.steps[down].GetStatus() == Skipped {
+				retry.Add(down)
+				queue = append(queue, down)
+			}
+		}
+	}
+
+	preset := w.preset
+	defer func() { w.preset = preset }()
+	w.preset = maps.Clone(preset)
+	if w.preset == nil {
+		w.preset = make(map[Steper]StepResult)
+	}
+	for step, state := range w.steps {
+		if !retry.Has(step) {
+			w.preset[step] = state.GetStepResult()
+		}
+	}
+	return w.do(ctx)
+}
@@ -0,0 +1,82 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryFailed(t *testing.T) {
+	t.Parallel()
+	// a → flaky → b → c (Always)
+	// d (Skipped by its own Condition)
+	var (
+		mu    sync.Mutex
+		ran   []string
+		fixed bool
+	)
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		ran = append(ran, name)
+	}
+	a := FuncO("a", func(context.Context) (int, error) {
+		record("a")
+		return 42, nil
+	})
+	flaky := FuncIO("flaky", func(_ context.Context, in int) (int, error) {
+		record("flaky")
+		if !fixed {
+			return 0, errors.New("flaky")
+		}
+		return in + 1, nil
+	})
+	newStep := func(name string) *Function[struct{}, struct{}] {
+		return Func(name, func(context.Context) error {
+			record(name)
+			return nil
+		})
+	}
+	b, c, d := newStep("b"), newStep("c"), newStep("d")
+	w := new(Workflow).Add(
+		Step(flaky).DependsOn(a).Input(func(_ context.Context, f *Function[int, int]) error {
+			f.Input = a.Output
+			return nil
+		}),
+		Step(b).DependsOn(flaky),
+		Step(c).DependsOn(b).When(Always),
+		Step(d).When(func(context.Context, map[Steper]StepResult) StepStatus { return Skipped }),
+	)
+	assert.Error(t, w.Do(context.Background()))
+	assert.ElementsMatch(t, []string{"a", "flaky", "c"}, ran)
+	assert.Equal(t, Skipped, w.StateOf(b).GetStatus())
+	finishedA := w.StateOf(a).GetStepResult().FinishedAt
+
+	ran, fixed = nil, true
+	assert.NoError(t, w.RetryFailed(context.Background()))
+	assert.Equal(t, []string{"flaky", "b"}, ran, "a and c succeeded already, d was not skipped by flaky")
+	assert.Equal(t, 43, flaky.Output, "reuses the output of a")
+	assert.Equal(t, finishedA, w.StateOf(a).GetStepResult().FinishedAt, "a keeps its result")
+	assert.Equal(t, Skipped, w.StateOf(d).GetStatus())
+	assert.True(t, w.IsTerminated())
+
+	t.Run("nothing to retry", func(t *testing.T) {
+		ran = nil
+		assert.NoError(t, w.RetryFailed(context.Background()))
+		assert.Empty(t, ran)
+	})
+	t.Run("Do runs everything again", func(t *testing.T) {
+		ran = nil
+		assert.NoError(t, w.Do(context.Background()))
+		assert.ElementsMatch(t, []string{"a", "flaky", "b", "c"}, ran)
+	})
+	t.Run("never ran", func(t *testing.T) {
+		ran = nil
+		w := new(Workflow).Add(Step(b).DependsOn(newStep("e")))
+		assert.NoError(t, w.RetryFailed(context.Background()))
+		assert.ElementsMatch(t, []string{"e", "b"}, ran)
+	})
+}
@@ -0,0 +1,144 @@
+package flow
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// plan is the dependency graph between the root steps of a Workflow, built
+// once per Do by preflight, and the state of scheduling it. Each root counts
+// its upstreams not terminated yet; a root becomes ready when the count drops
+// to zero, so tick only looks at ready roots instead of rescanning them all.
+type plan struct {
+	order []Steper            // root steps in dispatch order, see dispatchOrder.
+	rank  map[Steper]int      // root → its position in order.
+	ups   map[Steper][]Steper // root → its direct upstream roots.
+	downs map[Steper][]Steper // root → its direct downstream roots.
+
+	waiting   map[Steper]int // root → how many of its upstreams haven't terminated yet.
+	ready     readyQueue     // roots whose upstreams all terminated, not started yet.
+	remaining int            // roots not terminated yet.
+	finished  []Steper       // roots terminated by their worker since the last tick; guarded by statusChange.L.
+	drained   bool           // the Pending roots were canceled because the Workflow drains.
+}
+
+// newPlan builds the dependency graph of w's root steps, listed in order.
+func (w *Workflow) newPlan(order []Steper) *plan {
+	p := &plan{
+		order: order,
+		rank:  make(map[Steper]int, len(order)),
+		ups:   make(map[Steper][]Steper, len(order)),
+		downs: make(map[Steper][]Steper, len(order)),
+	}
+	for i, step := range order {
+		p.rank[step] = i
+	}
+	for _, step := range order {
+		for up := range w.UpstreamOf(step) {
+			p.ups[step] = append(p.ups[step], up)
+			p.downs[up] = append(p.downs[up], step)
+		}
+	}
+	return p
+}
+
+// layers sorts the roots topologically: each layer holds, in dispatch order,
+// the roots whose upstreams are all in earlier layers. Roots in, or
+// downstream of, a dependency cycle are in no layer.
+func (p *plan) layers() [][]Steper {
+	waiting := make(map[Steper]int, len(p.order))
+	var layer []Steper
+	for _, step := range p.order {
+		waiting[step] = len(p.ups[step])
+		if waiting[step] == 0 {
+			layer = append(layer, step)
+		}
+	}
+	var layers [][]Steper
+	for len(layer) > 0 {
+		layers = append(layers, layer)
+		var next []Steper
+		for _, step := range layer {
+			for _, down := range p.downs[step] {
+				if waiting[down]--; waiting[down] == 0 {
+					next = append(next, down)
+				}
+			}
+		}
+		slices.SortFunc(next, func(a, b Steper) int { return p.rank[a] - p.rank[b] })
+		layer = next
+	}
+	return layers
+}
+
+// sort returns the layers and, if some roots are in no layer, an
+// ErrCycleDependency mapping each of them to its upstreams in no layer
+// either.
+func (p *plan) sort() ([][]Steper, error) {
+	layers := p.layers()
+	var sorted Set[Steper]
+	for _, layer := range layers {
+		sorted.Add(layer...)
+	}
+	stepsInCycle := make(ErrCycleDependency)
+	for _, step := range p.order {
+		if sorted.Has(step) {
+			continue
+		}
+		for _, up := range p.ups[step] {
+			if !sorted.Has(up) {
+				stepsInCycle[step] = append(stepsInCycle[step], up)
+			}
+		}
+	}
+	if len(stepsInCycle) > 0 {
+		return layers, stepsInCycle
+	}
+	return layers, nil
+}
+
+// start resets the scheduling state: every root is waiting for all its
+// upstreams, and those without any are ready.
+func (p *plan) start() {
+	p.waiting = make(map[Steper]int, len(p.order))
+	p.ready = readyQueue{rank: p.rank}
+	for _, step := range p.order {
+		p.waiting[step] = len(p.ups[step])
+		if p.waiting[step] == 0 {
+			p.ready.push(step)
+		}
+	}
+	p.remaining = len(p.order)
+	p.finished = nil
+	p.drained = false
+}
+
+// terminated records that root step reached a terminal status: its
+// downstreams whose upstreams have all terminated become ready.
+func (p *plan) terminated(step Steper) {
+	p.remaining--
+	for _, down := range p.downs[step] {
+		if p.waiting[down]--; p.waiting[down] == 0 {
+			p.ready.push(down)
+		}
+	}
+}
+
+// readyQueue is a heap of root steps, lowest rank (dispatch order) first.
+type readyQueue struct {
+	steps []Steper
+	rank  map[Steper]int
+}
+
+func (q *readyQueue) push(step Steper) { heap.Push(q, step) }
+func (q *readyQueue) pop() Steper      { return heap.Pop(q).(Steper) }
+
+func (q *readyQueue) Len() int           { return len(q.steps) }
+func (q *readyQueue) Less(i, j int) bool { return q.rank[q.steps[i]] < q.rank[q.steps[j]] }
+func (q *readyQueue) Swap(i, j int)      { q.steps[i], q.steps[j] = q.steps[j], q.steps[i] }
+func (q *readyQueue) Push(x any)         { q.steps = append(q.steps, x.(Steper)) }
+func (q *readyQueue) Pop() any {
+	last := q.steps[len(q.steps)-1]
+	q.steps = q.steps[:len(q.steps)-1]
+	return last
+}
@@ -0,0 +1,118 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// layered builds a Workflow of n NoOp steps in layers of width steps, each
+// step depending on two steps of the previous layer.
+func layered(n, width int) *Workflow {
+	w := new(Workflow)
+	steps := make([]Steper, n)
+	for i := range steps {
+		steps[i] = NoOp(fmt.Sprint(i))
+	}
+	for i, step := range steps {
+		if i < width {
+			w.Add(Step(step))
+			continue
+		}
+		prev := i - width
+		w.Add(Step(step).DependsOn(steps[prev], steps[prev-prev%width+(prev+1)%width]))
+	}
+	return w
+}
+
+func TestIndex(t *testing.T) {
+	t.Parallel()
+	t.Run("sub-workflow grows after being added", func(t *testing.T) {
+		inner := new(Workflow)
+		w := new(Workflow).Add(Step(inner))
+		a, b := NoOp("a"), NoOp("b")
+		inner.Add(Step(a))
+		assert.Equal(t, inner, w.RootOf(a))
+		assert.Same(t, inner.StateOf(a), w.StateOf(a))
+
+		w.Add(Step(b).DependsOn(a))
+		assert.Len(t, w.steps, 2)
+		assert.Contains(t, w.UpstreamOf(b), Steper(inner))
+		assert.NoError(t, w.Do(context.Background()))
+	})
+	t.Run("sub-workflow reset after being added", func(t *testing.T) {
+		sub := new(SubWorkflow)
+		a := NoOp("a")
+		sub.Add(Step(a))
+		w := new(Workflow).Add(Step(sub))
+		assert.Equal(t, sub, w.RootOf(a))
+		sub.Reset()
+		assert.Nil(t, w.RootOf(a))
+		assert.Nil(t, w.StateOf(a))
+		assert.NotPanics(t, func() { w.Add(Step(a)) })
+		assert.Len(t, w.steps, 2)
+	})
+	t.Run("absorbed roots", func(t *testing.T) {
+		a, b := NoOp("a"), NoOp("b")
+		w := new(Workflow).Add(Step(b).DependsOn(a))
+		named := &NamedStep{Name: "A", Steper: a}
+		w.Add(Step(named))
+		assert.Equal(t, named, w.RootOf(a))
+		assert.Same(t, w.StateOf(named), w.StateOf(a))
+		assert.Equal(t, []Steper{b, named}, w.order)
+		assert.Contains(t, w.UpstreamOf(b), Steper(named))
+	})
+	t.Run("root absorbed by a new sub-workflow", func(t *testing.T) {
+		a := NoOp("a")
+		w := new(Workflow).Add(Step(a))
+		inner := new(Workflow).Add(Step(a))
+		w.Add(Step(inner))
+		assert.Equal(t, inner, w.RootOf(a))
+		assert.Same(t, inner.StateOf(a), w.StateOf(a))
+		assert.NoError(t, w.Do(context.Background()))
+		assert.Equal(t, Succeeded, w.StateOf(a).GetStatus())
+	})
+}
+
+func TestSchedule_Large(t *testing.T) {
+	t.Parallel()
+	w := layered(5_000, 50)
+	assert.NoError(t, w.Do(context.Background()))
+	assert.True(t, w.IsTerminated())
+	assert.Empty(t, w.Validate())
+}
+
+func BenchmarkSchedule(b *testing.B) {
+	for _, n := range []int{1_000, 5_000, 20_000} {
+		for _, shape := range []struct {
+			name  string
+			width int
+		}{
+			{"chain", 1},
+			{"layers", 100},
+			{"flat", n},
+		} {
+			b.Run(fmt.Sprintf("%s/%d", shape.name, n), func(b *testing.B) {
+				w := layered(n, shape.width)
+				b.ResetTimer()
+				for range b.N {
+					if err := w.Do(context.Background()); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkAdd(b *testing.B) {
+	for _, n := range []int{1_000, 5_000, 20_000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for range b.N {
+				layered(n, 100)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RunWithSignals runs w.Do(ctx), turning OS signals into a graceful
+// shutdown, e.g. for a CLI receiving SIGTERM during a node drain:
+//
+//	report, err := flow.RunWithSignals(ctx, w, 30*time.Second)
+//	fmt.Fprint(os.Stderr, report)
+//
+// The first of sigs (os.Interrupt and SIGTERM if none) drains w (see
+// Workflow.Drain), even if it comes before Do started any Step: no new Step
+// starts, they are settled Canceled with an ErrInterrupted, and running
+// Steps get grace (on Option.Clock) to terminate before their context is
+// canceled with ErrHardTimeout; a negative grace waits for them. A second
+// signal cancels them right away.
+//
+// It returns what w.Do returned, and a RunReport of the run, whatever its
+// outcome.
+func RunWithSignals(ctx context.Context, w *Workflow, grace time.Duration, sigs ...os.Signal) (*RunReport, error) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, sigs...)
+	defer signal.Stop(received)
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	// w drains with drain as with a parent Workflow, even if the signal comes
+	// before Do is ready to drain.
+	drain := &drainer{drained: make(chan struct{}), stopped: make(chan struct{})}
+	defer drain.stop()
+	ctx = drainKey.With(ctx, drain)
+	clk := w.clock()
+	report := &RunReport{Start: clk.Now()}
+	done := make(chan error, 1)
+	go func() { done <- w.Do(ctx) }()
+
+	var err error
+wait:
+	for {
+		select {
+		case err = <-done:
+			break wait
+		case sig := <-received:
+			interrupted := ErrInterrupted{Signal: sig}
+			if report.Signal == nil {
+				report.Signal = sig
+				drain.begin(interrupted)
+				if grace >= 0 {
+					timer := clk.AfterFunc(grace, func() { cancel(ErrHardTimeout) })
+					defer timer.Stop()
+				}
+				continue
+			}
+			cancel(interrupted)
+		}
+	}
+	report.End = clk.Now()
+	report.Err = err
+	report.Results = make(ErrWorkflow)
+	for _, step := range w.Steps() {
+		report.Results[step] = w.StateOf(step).GetStepResult()
+	}
+	return report, err
+}
+
+// RunReport is the final status of a run of RunWithSignals.
+type RunReport struct {
+	// Signal is the first signal received, nil if none.
+	Signal     os.Signal
+	Start, End time.Time
+	// Err is what Workflow.Do returned.
+	Err error
+	// Results is the StepResult of every root Step.
+	Results ErrWorkflow
+}
+
+// String renders the report for humans:
+//
+//	interrupted by terminated after 1m30s
+//	a: [Succeeded]
+//	b: [Canceled]
+//	    interrupted by terminated
+func (r *RunReport) String() string {
+	var b strings.Builder
+	elapsed := r.End.Sub(r.Start)
+	switch {
+	case r.Signal != nil:
+		fmt.Fprintf(&b, "interrupted by %s after %s\n", r.Signal, elapsed)
+	case r.Err != nil:
+		fmt.Fprintf(&b, "failed after %s\n", elapsed)
+	default:
+		fmt.Fprintf(&b, "succeeded after %s\n", elapsed)
+	}
+	b.WriteString(r.Results.Error())
+	return b.String()
+}
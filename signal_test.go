@@ -0,0 +1,116 @@
+//go:build unix
+
+package flow_test
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests send signals to the test process itself, so they don't run
+// in parallel.
+
+func raise(t *testing.T, sig os.Signal) {
+	t.Helper()
+	p, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, p.Signal(sig))
+}
+
+func TestRunWithSignals(t *testing.T) {
+	t.Run("drains, then reports", func(t *testing.T) {
+		w := new(flow.Workflow)
+		b := flow.NoOp("b")
+		a := flow.Func("a", func(ctx context.Context) error {
+			raise(t, syscall.SIGUSR1)
+			// pending Steps are canceled while a still runs
+			assert.Eventually(t, func() bool { return w.StateOf(b).GetStatus() == flow.Canceled }, time.Second, time.Millisecond)
+			return nil
+		})
+		w.Add(flow.Step(b).DependsOn(a))
+
+		report, err := flow.RunWithSignals(context.Background(), w, time.Minute, syscall.SIGUSR1)
+		require.Error(t, err)
+		assert.Equal(t, syscall.SIGUSR1, report.Signal)
+		assert.Equal(t, err, report.Err)
+		assert.Equal(t, flow.Succeeded, report.Results[a].Status)
+		assert.Equal(t, flow.Canceled, report.Results[b].Status)
+		var interrupted flow.ErrInterrupted
+		require.ErrorAs(t, report.Results[b].Err, &interrupted)
+		assert.Equal(t, syscall.SIGUSR1, interrupted.Signal)
+		assert.Contains(t, report.String(), "interrupted by user defined signal 1 after")
+		assert.Contains(t, report.String(), "b: [Canceled]")
+	})
+	t.Run("running steps are canceled after the grace period", func(t *testing.T) {
+		var cause error
+		a := flow.Func("a", func(ctx context.Context) error {
+			raise(t, syscall.SIGUSR1)
+			<-ctx.Done()
+			cause = context.Cause(ctx)
+			return ctx.Err()
+		})
+		w := new(flow.Workflow).Add(flow.Step(a))
+		report, err := flow.RunWithSignals(context.Background(), w, 0, syscall.SIGUSR1)
+		require.Error(t, err)
+		assert.Equal(t, flow.Canceled, report.Results[a].Status)
+		assert.ErrorIs(t, cause, flow.ErrHardTimeout)
+	})
+	t.Run("a second signal cancels right away", func(t *testing.T) {
+		var cause error
+		w := new(flow.Workflow)
+		b := flow.NoOp("b")
+		a := flow.Func("a", func(ctx context.Context) error {
+			raise(t, syscall.SIGUSR1)
+			assert.Eventually(t, func() bool { return w.StateOf(b).GetStatus() == flow.Canceled }, time.Second, time.Millisecond)
+			raise(t, syscall.SIGUSR1)
+			<-ctx.Done()
+			cause = context.Cause(ctx)
+			return ctx.Err()
+		})
+		w.Add(flow.Step(b).DependsOn(a))
+		report, err := flow.RunWithSignals(context.Background(), w, -1, syscall.SIGUSR1)
+		require.Error(t, err)
+		assert.Equal(t, flow.Canceled, report.Results[a].Status)
+		assert.ErrorAs(t, cause, new(flow.ErrInterrupted))
+	})
+	t.Run("a signal before the first step starts drains", func(t *testing.T) {
+		a := flow.NoOp("a")
+		early := &beforeDo{raise: func() {
+			raise(t, syscall.SIGUSR1)
+			time.Sleep(50 * time.Millisecond) // let RunWithSignals see it before Do can drain
+		}}
+		w := new(flow.Workflow).Add(flow.Step(early), flow.Step(a))
+		report, err := flow.RunWithSignals(context.Background(), w, time.Minute, syscall.SIGUSR1)
+		require.Error(t, err)
+		assert.Equal(t, syscall.SIGUSR1, report.Signal)
+		assert.Equal(t, flow.Canceled, report.Results[a].Status)
+		assert.ErrorAs(t, report.Results[a].Err, new(flow.ErrInterrupted), "drained, not canceled")
+	})
+	t.Run("no signal", func(t *testing.T) {
+		w := new(flow.Workflow).Add(flow.Step(flow.NoOp("a")))
+		report, err := flow.RunWithSignals(context.Background(), w, time.Minute, syscall.SIGUSR1)
+		require.NoError(t, err)
+		assert.Nil(t, report.Signal)
+		assert.Contains(t, report.String(), "succeeded after")
+		assert.Contains(t, report.String(), "a: [Succeeded]")
+	})
+}
+
+// beforeDo is a sub-workflow calling raise when its parent's Do starts,
+// before the parent runs any Step.
+type beforeDo struct {
+	flow.Workflow
+	raise func()
+}
+
+func (b *beforeDo) InheritOption(parent flow.WorkflowOption) func() {
+	b.raise()
+	return b.Workflow.InheritOption(parent)
+}
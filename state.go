@@ -3,6 +3,7 @@ package flow
 import (
 	"context"
 	"sync"
+	"time"
 )
 
 // State is the per-step bookkeeping that a Workflow keeps for every Step it
@@ -69,6 +70,24 @@ func (s *State) SetStepResult(r StepResult) {
 	s.StepResult = r
 }
 
+// markReady records now as ReadyAt, unless the step was already found ready
+// by an earlier tick (and is still waiting for a lease).
+func (s *State) markReady(now time.Time) {
+	s.Lock()
+	defer s.Unlock()
+	if s.ReadyAt.IsZero() {
+		s.ReadyAt = now
+	}
+}
+
+// markRunning sets the status to Running and records now as StartedAt.
+func (s *State) markRunning(now time.Time) {
+	s.Lock()
+	defer s.Unlock()
+	s.Status = Running
+	s.StartedAt = now
+}
+
 // GetError is a convenience over GetStepResult().Err.
 func (s *State) GetError() error { return s.GetStepResult().Err }
 
@@ -55,9 +55,14 @@ type StepConfig struct {
 // mutators win for fields they touch (so Timeout/When/Retry follow
 // "last-one-wins").
 type StepOption struct {
-	RetryOption *RetryOption   // nil means: no retry, run once.
-	Condition   Condition      // nil means: use the package-level DefaultCondition (AllSucceeded).
-	Timeout     *time.Duration // nil means: no step-level deadline (the step runs until ctx is done).
+	RetryOption  *RetryOption   // nil means: no retry, run once.
+	Condition    Condition      // nil means: use the package-level DefaultCondition (AllSucceeded).
+	Timeout      *time.Duration // nil means: no step-level deadline (the step runs until ctx is done).
+	DontFailFast bool           // true means: this step's failure doesn't trigger Workflow.Option.FailFast.
+
+	// IdempotencyKey, if non-nil, makes the step run at most once per key;
+	// see AddSteps.IdempotencyKey.
+	IdempotencyKey func(context.Context, Steper) string
 }
 
 // Steps registers one or more independent Steps to be added into the Workflow.
@@ -287,6 +292,26 @@ func (as AddSteps) When(cond Condition) AddSteps {
 	return as
 }
 
+// DontFailFast opts the step(s) out of Workflow.Option.FailFast: their
+// failure no longer cancels the rest of the workflow. Use it for
+// best-effort steps whose failure is tolerable. The step itself is still
+// canceled when another step triggers FailFast.
+//
+//	w.Option.FailFast = &failFast
+//	w.Add(
+//	    Steps(provisionA, provisionB), // either failing cancels the other.
+//	    Steps(notify).DontFailFast(),  // a failed notification is tolerated.
+//	    Steps(cleanup).DependsOn(provisionA, provisionB).When(flow.Always),
+//	)
+func (as AddSteps) DontFailFast() AddSteps {
+	for step := range as {
+		as[step].Option = append(as[step].Option, func(so *StepOption) {
+			so.DontFailFast = true
+		})
+	}
+	return as
+}
+
 // Retry configures retry behavior for the step. The mutator(s) are applied to
 // a fresh RetryOption seeded from DefaultRetryOption (so calling Retry with
 // no mutator — e.g. Retry(nil) — opts in to the default retry policy).
@@ -323,6 +348,32 @@ func (as AddSteps) Retry(opts ...func(*RetryOption)) AddSteps {
 	return as
 }
 
+// IdempotencyKey makes the step(s) run at most once per key across runs
+// sharing the same Option.IdempotencyStore. key is computed when the step
+// starts; "" opts that run out.
+//
+// If the key already completed, the step is settled Succeeded (with
+// Succeed(ErrIdempotent) as Err) without invoking Do nor BeforeStep
+// callbacks: its state is restored instead, then AfterStep callbacks run
+// with a nil error, so Output callbacks see the restored Output. The state
+// is that of the first layer of the step's Unwrap chain implementing
+// json.Marshaler and json.Unmarshaler, e.g. *Function's Input and Output.
+//
+// Otherwise the step runs, and once it succeeded its key and state are put
+// in the store; failing to do so fails the step. Last call wins.
+//
+//	w.Add(flow.Step(createRG).IdempotencyKey(func(ctx context.Context, _ flow.Steper) string {
+//	    return "create-rg/" + deploymentID
+//	}))
+func (as AddSteps) IdempotencyKey(key func(context.Context, Steper) string) AddSteps {
+	for step := range as {
+		as[step].Option = append(as[step].Option, func(so *StepOption) {
+			so.IdempotencyKey = key
+		})
+	}
+	return as
+}
+
 // AddToWorkflow makes AddSteps satisfy Builder so it can be passed to
 // Workflow.Add directly.
 func (as AddSteps) AddToWorkflow() map[Steper]*StepConfig { return as }
@@ -372,6 +423,18 @@ func (as AddStep[S]) When(when Condition) AddStep[S] {
 	return as
 }
 
+// DontFailFast — typed shim; see AddSteps.DontFailFast.
+func (as AddStep[S]) DontFailFast() AddStep[S] {
+	as.AddSteps = as.AddSteps.DontFailFast()
+	return as
+}
+
+// IdempotencyKey — typed shim; see AddSteps.IdempotencyKey.
+func (as AddStep[S]) IdempotencyKey(key func(context.Context, Steper) string) AddStep[S] {
+	as.AddSteps = as.AddSteps.IdempotencyKey(key)
+	return as
+}
+
 // Retry — typed shim; see AddSteps.Retry.
 func (as AddStep[S]) Retry(fns ...func(*RetryOption)) AddStep[S] {
 	as.AddSteps = as.AddSteps.Retry(fns...)
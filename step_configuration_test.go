@@ -3,6 +3,7 @@ package flow
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -81,7 +82,9 @@ func TestBeforeAfter(t *testing.T) {
 			return nil
 		})
 		beforeContext = func(ctx context.Context, _ Steper) (context.Context, error) {
-			assert.Equal(t, "context.TODO", fmt.Sprint(ctx))
+			// the caller's context, carrying the Blackboard of the run.
+			assert.True(t, strings.HasPrefix(fmt.Sprint(ctx), "context.TODO"), fmt.Sprint(ctx))
+			assert.NotNil(t, BlackboardFrom(ctx))
 			return context.Background(), nil
 		}
 		beforeInc = func(ctx context.Context, _ Steper) (context.Context, error) {
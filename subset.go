@@ -0,0 +1,91 @@
+package flow
+
+import (
+	"maps"
+	"slices"
+)
+
+// Subset returns a new Workflow running only targets and the steps they
+// transitively depend on, e.g. to produce a single artifact:
+//
+//	w.Subset(build).Do(ctx) // runs build and its ancestors, nothing else
+//
+// The new Workflow shares the Step instances, the StepConfig of each Step
+// (upstreams, callbacks and options) and a copy of w.Option. Targets are
+// normalised to their root steps: targeting a Step inside a sub-workflow
+// runs the whole sub-workflow. Targets not in w are ignored.
+func (w *Workflow) Subset(targets ...Steper) *Workflow {
+	p := w.topology()
+	keep := make(Set[Steper])
+	for _, target := range targets {
+		if root := w.RootOf(target); root != nil && !keep.Has(root) {
+			keep.Add(root)
+			reach(root, p.ups, keep)
+		}
+	}
+	return w.subset(keep)
+}
+
+// From returns a new Workflow running only steps and the steps transitively
+// depending on them, e.g. to re-run a failed Step and what it feeds:
+//
+//	w.From(migrate).Do(ctx) // runs migrate and its descendants, nothing else
+//
+// The direct upstreams of those steps that are left out are kept in the new
+// Workflow, preset as Succeeded: they never run, and each run starts with
+// them already succeeded, so Conditions see them as they would in w, and
+// with the values they published on a copy of w's Blackboard. Like Subset,
+// the new Workflow shares the Step instances, their StepConfig and a copy of
+// w.Option, and steps are normalised to their root steps.
+func (w *Workflow) From(steps ...Steper) *Workflow {
+	p := w.topology()
+	keep := make(Set[Steper])
+	for _, step := range steps {
+		if root := w.RootOf(step); root != nil && !keep.Has(root) {
+			keep.Add(root)
+			reach(root, p.downs, keep)
+		}
+	}
+	return w.subset(keep)
+}
+
+// subset builds a Workflow of the roots in keep, with the upstreams they
+// have outside keep preset as Succeeded.
+func (w *Workflow) subset(keep Set[Steper]) *Workflow {
+	p := w.topology()
+	sub := &Workflow{
+		Option: w.Option,
+		// the Steps are built already, don't build (and reset) them again.
+		StepBuilder: StepBuilder{built: maps.Clone(w.built)},
+		steps:       make(map[Steper]*State),
+		preset:      make(map[Steper]StepResult),
+	}
+	for root := range keep {
+		for _, up := range p.ups[root] {
+			if !keep.Has(up) {
+				sub.preset[up] = StepResult{Status: Succeeded}
+			}
+		}
+	}
+	for _, root := range w.order {
+		if keep.Has(root) || sub.preset[root].Status == Succeeded {
+			sub.addStep(root, nil)
+		}
+	}
+	for _, root := range w.order {
+		if config := w.steps[root].Config; keep.Has(root) && config != nil {
+			sub.addStep(root, &StepConfig{
+				Upstreams: maps.Clone(config.Upstreams),
+				Before:    slices.Clone(config.Before),
+				After:     slices.Clone(config.After),
+				Option:    slices.Clone(config.Option),
+			})
+		}
+	}
+	if len(sub.preset) > 0 {
+		// the preset steps published their values in w's runs.
+		sub.board.Store(w.Blackboard().clone())
+	}
+	sub.reset()
+	return sub
+}
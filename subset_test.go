@@ -0,0 +1,102 @@
+package flow
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubset(t *testing.T) {
+	t.Parallel()
+	// a → b → d
+	//  ↘ c ↗
+	// e (unrelated)
+	var (
+		mu  sync.Mutex
+		ran []string
+	)
+	record := func(name string) *Function[struct{}, struct{}] {
+		return Func(name, func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			ran = append(ran, name)
+			return nil
+		})
+	}
+	a, b, c, d, e := record("a"), record("b"), record("c"), record("d"), record("e")
+	newWorkflow := func() *Workflow {
+		ran = nil
+		return new(Workflow).Add(
+			Steps(b, c).DependsOn(a),
+			Step(d).DependsOn(b, c),
+			Step(e),
+		)
+	}
+	t.Run("Subset runs the targets and their ancestors", func(t *testing.T) {
+		w := newWorkflow()
+		sub := w.Subset(b)
+		assert.NoError(t, sub.Do(context.Background()))
+		assert.Equal(t, []string{"a", "b"}, ran)
+		assert.Equal(t, Pending, w.StateOf(a).GetStatus(), "w is left alone")
+		assert.Nil(t, sub.RootOf(c))
+	})
+	t.Run("From runs the steps and their descendants", func(t *testing.T) {
+		w := newWorkflow()
+		sub := w.From(c)
+		assert.Equal(t, Succeeded, sub.StateOf(a).GetStatus(), "outside upstreams are preset")
+		assert.Equal(t, Succeeded, sub.StateOf(b).GetStatus(), "d depends on b")
+		assert.Nil(t, sub.RootOf(e))
+		assert.NoError(t, sub.Do(context.Background()))
+		assert.ElementsMatch(t, []string{"c", "d"}, ran)
+
+		// preset steps survive Reset and further runs.
+		ran = nil
+		assert.NoError(t, sub.Reset())
+		assert.Equal(t, Succeeded, sub.StateOf(a).GetStatus())
+		assert.NoError(t, sub.Do(context.Background()))
+		assert.ElementsMatch(t, []string{"c", "d"}, ran)
+	})
+	t.Run("StepConfig is preserved", func(t *testing.T) {
+		var got int
+		w := new(Workflow).Add(
+			Step(b).DependsOn(a),
+			Step(c).DependsOn(b).When(Always).Input(func(context.Context, *Function[struct{}, struct{}]) error {
+				got++
+				return nil
+			}),
+		)
+		sub := w.From(b)
+		assert.Contains(t, sub.UpstreamOf(c), Steper(b))
+		assert.NotNil(t, sub.StateOf(c).Option().Condition)
+		assert.NoError(t, sub.Do(context.Background()))
+		assert.Equal(t, 1, got)
+	})
+	t.Run("Conditions see preset upstreams", func(t *testing.T) {
+		w := new(Workflow).Add(
+			Step(c).DependsOn(a, b).When(AnySucceeded),
+		)
+		ran = nil
+		assert.NoError(t, w.From(c).Do(context.Background()))
+		assert.Equal(t, []string{"c"}, ran)
+	})
+	t.Run("steps are normalised to roots", func(t *testing.T) {
+		x := record("x")
+		inner := new(Workflow).Add(Step(x))
+		w := new(Workflow).Add(
+			Step(inner).DependsOn(a),
+			Step(d).DependsOn(inner),
+		)
+		ran = nil
+		sub := w.Subset(x)
+		assert.Equal(t, Steper(inner), sub.RootOf(x))
+		assert.NoError(t, sub.Do(context.Background()))
+		assert.Equal(t, []string{"a", "x"}, ran)
+	})
+	t.Run("unknown steps", func(t *testing.T) {
+		w := newWorkflow()
+		assert.True(t, w.Subset(NoOp("other")).Empty())
+		assert.True(t, w.From().Empty())
+	})
+}
@@ -0,0 +1,196 @@
+package flow
+
+import (
+	"context"
+	"maps"
+)
+
+// topology returns the dependency graph of w's root steps, in the order they
+// were added. It is built on first use and kept until the next Add, or until
+// the upstreams of the root steps change, e.g. when a parent Workflow adds a
+// dependency between two steps of w.
+func (w *Workflow) topology() *plan {
+	w.topoMu.Lock()
+	defer w.topoMu.Unlock()
+	if edges := w.countEdges(); w.topo == nil || w.edges != edges {
+		w.topo = w.newPlan(w.order)
+		w.edges = edges
+	}
+	return w.topo
+}
+
+// countEdges counts the upstreams declared on the root steps. Upstreams are
+// never removed, so the count changes with them.
+func (w *Workflow) countEdges() int {
+	n := 0
+	for _, state := range w.steps {
+		n += len(state.Upstreams())
+	}
+	return n
+}
+
+// topologyQuerier is implemented by Workflow and anything embedding it.
+type topologyQuerier interface {
+	DownstreamOf(Steper) map[Steper]StepResult
+	AncestorsOf(Steper) Set[Steper]
+	DescendantsOf(Steper) Set[Steper]
+	ImpactOf(Steper) map[Steper]StepStatus
+}
+
+// innerOf returns the sub-workflow in the tree of root that step belongs to,
+// or nil if step sits in root's tree outside any sub-workflow.
+func (w *Workflow) innerOf(root, step Steper) topologyQuerier {
+	if _, ok := w.index.states[step]; ok {
+		return nil
+	}
+	var inner topologyQuerier
+	Traverse(root, func(s Steper, walked []Steper) TraverseDecision {
+		if sub, ok := s.(subWorkflow); ok && s != Steper(w) {
+			if sub.RootOf(step) != nil {
+				inner, _ = s.(topologyQuerier)
+				return TraverseStop
+			}
+			return TraverseEndBranch
+		}
+		return TraverseContinue
+	})
+	return inner
+}
+
+// DownstreamOf returns each direct downstream of `step` mapped to that
+// downstream's current StepResult, the reverse of UpstreamOf. Identities are
+// normalised to root steps.
+//
+// For a step inside a sub-workflow, the downstreams are its siblings in that
+// sub-workflow, normalised to the sub-workflow's root steps.
+func (w *Workflow) DownstreamOf(step Steper) map[Steper]StepResult {
+	root := w.RootOf(step)
+	if root == nil {
+		return nil
+	}
+	if inner := w.innerOf(root, step); inner != nil {
+		return inner.DownstreamOf(step)
+	}
+	rv := make(map[Steper]StepResult)
+	for _, down := range w.topology().downs[root] {
+		rv[down] = w.StateOf(down).GetStepResult()
+	}
+	return rv
+}
+
+// AncestorsOf returns the steps `step` transitively depends on: its
+// upstreams, their upstreams, and so on, normalised to root steps.
+//
+// For a step inside a sub-workflow, that includes its ancestors in the
+// sub-workflow and the ancestors of the sub-workflow itself, as the
+// sub-workflow doesn't start before them.
+func (w *Workflow) AncestorsOf(step Steper) Set[Steper] {
+	root := w.RootOf(step)
+	if root == nil {
+		return nil
+	}
+	rv := make(Set[Steper])
+	if inner := w.innerOf(root, step); inner != nil {
+		rv.Union(inner.AncestorsOf(step))
+	}
+	reach(root, w.topology().ups, rv)
+	return rv
+}
+
+// DescendantsOf returns the steps transitively depending on `step`: its
+// downstreams, their downstreams, and so on, normalised to root steps.
+//
+// For a step inside a sub-workflow, that includes its descendants in the
+// sub-workflow and the descendants of the sub-workflow itself, as they wait
+// for the sub-workflow to terminate.
+func (w *Workflow) DescendantsOf(step Steper) Set[Steper] {
+	root := w.RootOf(step)
+	if root == nil {
+		return nil
+	}
+	rv := make(Set[Steper])
+	if inner := w.innerOf(root, step); inner != nil {
+		rv.Union(inner.DescendantsOf(step))
+	}
+	reach(root, w.topology().downs, rv)
+	return rv
+}
+
+// reach adds to found every step reachable from step following edges.
+func reach(step Steper, edges map[Steper][]Steper, found Set[Steper]) {
+	queue := []Steper{step}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		for _, s := range edges[next] {
+			if !found.Has(s) {
+				found.Add(s)
+				queue = append(queue, s)
+			}
+		}
+	}
+}
+
+// TopologicalLayers sorts the root steps of w: each layer holds the steps
+// whose upstreams are all in earlier layers, so the steps of a layer may run
+// in parallel. Within a layer, steps are in the order they were added, see
+// Add: those added by one Builder, e.g. Steps(b, c), are ordered by String.
+//
+// Steps inside sub-workflows are not listed, ask the sub-workflow instead.
+// If the dependencies have a cycle, TopologicalLayers returns the layers it
+// could sort together with an ErrCycleDependency.
+func (w *Workflow) TopologicalLayers() ([][]Steper, error) {
+	return w.topology().sort()
+}
+
+// ImpactOf returns the blast radius of `step`: the steps that would not run
+// if it failed while every other step succeeded, each mapped to the status
+// their Condition settles them with (Skipped or Canceled). A step whose
+// Condition still runs it (e.g. Always) is assumed to succeed, so it shields
+// its own downstreams.
+//
+// Conditions are evaluated with a background context and the hypothetical
+// upstream results. When `step` is wrapped by, or nested in, another root
+// step, that root is reported as Failed too; for a step inside a
+// sub-workflow, the impact inside the sub-workflow is included.
+func (w *Workflow) ImpactOf(step Steper) map[Steper]StepStatus {
+	root := w.RootOf(step)
+	if root == nil {
+		return nil
+	}
+	impact := make(map[Steper]StepStatus)
+	if inner := w.innerOf(root, step); inner != nil {
+		maps.Copy(impact, inner.ImpactOf(step))
+	}
+	if root != step {
+		impact[root] = Failed
+	}
+	p := w.topology()
+	descendants := make(Set[Steper])
+	reach(root, p.downs, descendants)
+	status := map[Steper]StepStatus{root: Failed}
+	layers, _ := p.sort()
+	for _, layer := range layers {
+		for _, s := range layer {
+			if !descendants.Has(s) {
+				continue
+			}
+			ups := make(map[Steper]StepResult, len(p.ups[s]))
+			for _, up := range p.ups[s] {
+				ups[up] = StepResult{Status: Succeeded}
+				if st, ok := status[up]; ok {
+					ups[up] = StepResult{Status: st}
+				}
+			}
+			cond := DefaultCondition
+			if option := w.steps[s].Option(); option != nil && option.Condition != nil {
+				cond = option.Condition
+			}
+			if st := cond(context.Background(), ups); st.IsTerminated() {
+				status[s] = st
+				impact[s] = st
+			}
+		}
+	}
+	return impact
+}
@@ -0,0 +1,109 @@
+package flow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopology(t *testing.T) {
+	t.Parallel()
+	// a → b → d
+	//  ↘ c ↗   ↘ e (Always)
+	a, b, c, d, e := NoOp("a"), NoOp("b"), NoOp("c"), NoOp("d"), NoOp("e")
+	newWorkflow := func() *Workflow {
+		return new(Workflow).Add(
+			Steps(b, c).DependsOn(a),
+			Step(d).DependsOn(b, c),
+			Step(e).DependsOn(d).When(Always),
+		)
+	}
+	t.Run("DownstreamOf", func(t *testing.T) {
+		w := newWorkflow()
+		assert.Equal(t, map[Steper]StepResult{b: {Status: Pending}, c: {Status: Pending}}, w.DownstreamOf(a))
+		assert.Empty(t, w.DownstreamOf(e))
+		assert.NotNil(t, w.DownstreamOf(e))
+		assert.Nil(t, w.DownstreamOf(NoOp("other")))
+	})
+	t.Run("AncestorsOf and DescendantsOf", func(t *testing.T) {
+		w := newWorkflow()
+		assert.Equal(t, Set[Steper]{a: {}, b: {}, c: {}}, w.AncestorsOf(d))
+		assert.Empty(t, w.AncestorsOf(a))
+		assert.Equal(t, Set[Steper]{b: {}, c: {}, d: {}, e: {}}, w.DescendantsOf(a))
+		assert.Equal(t, Set[Steper]{d: {}, e: {}}, w.DescendantsOf(c))
+	})
+	t.Run("TopologicalLayers", func(t *testing.T) {
+		w := newWorkflow()
+		layers, err := w.TopologicalLayers()
+		assert.NoError(t, err)
+		assert.Equal(t, [][]Steper{{a}, {b, c}, {d}, {e}}, layers)
+	})
+	t.Run("TopologicalLayers with cycle", func(t *testing.T) {
+		w := newWorkflow()
+		w.Add(Step(a).DependsOn(d))
+		layers, err := w.TopologicalLayers()
+		assert.ErrorAs(t, err, new(ErrCycleDependency))
+		assert.Empty(t, layers)
+	})
+	t.Run("queries follow Add", func(t *testing.T) {
+		w := newWorkflow()
+		assert.NotContains(t, w.DescendantsOf(e), Steper(NoOp("f")))
+		f := NoOp("f")
+		w.Add(Step(f).DependsOn(e))
+		assert.Contains(t, w.DescendantsOf(a), Steper(f))
+	})
+	t.Run("queries follow a dependency added by the parent", func(t *testing.T) {
+		x, y := NoOp("x"), NoOp("y")
+		inner := new(Workflow).Add(Step(x), Step(y))
+		w := new(Workflow).Add(Step(inner))
+		assert.Empty(t, inner.DownstreamOf(x))
+		w.Add(Step(y).DependsOn(x))
+		assert.Equal(t, map[Steper]StepResult{y: {Status: Pending}}, inner.DownstreamOf(x))
+		assert.Equal(t, map[Steper]StepResult{y: {Status: Pending}}, w.DownstreamOf(x))
+		layers, err := inner.TopologicalLayers()
+		assert.NoError(t, err)
+		assert.Equal(t, [][]Steper{{x}, {y}}, layers)
+	})
+	t.Run("ImpactOf", func(t *testing.T) {
+		w := newWorkflow()
+		assert.Equal(t, map[Steper]StepStatus{b: Skipped, c: Skipped, d: Skipped}, w.ImpactOf(a),
+			"e runs Always, so it's not impacted")
+		assert.Equal(t, map[Steper]StepStatus{d: Skipped}, w.ImpactOf(c))
+		assert.Empty(t, w.ImpactOf(e))
+	})
+	t.Run("normalised to root steps", func(t *testing.T) {
+		w := newWorkflow()
+		named := &NamedStep{Name: "B", Steper: b}
+		w.Add(Step(named))
+		assert.Contains(t, w.DownstreamOf(a), Steper(named))
+		assert.Equal(t, Set[Steper]{a: {}, named: {}, c: {}}, w.AncestorsOf(d))
+		assert.Equal(t, map[Steper]StepStatus{named: Failed, d: Skipped}, w.ImpactOf(b))
+	})
+	t.Run("nested sub-workflow", func(t *testing.T) {
+		x, y, z := NoOp("x"), NoOp("y"), NoOp("z")
+		inner := new(Workflow).Add(Step(y).DependsOn(x))
+		after := NoOp("after")
+		w := new(Workflow).Add(
+			Step(inner).DependsOn(z),
+			Step(after).DependsOn(inner),
+		)
+		assert.Equal(t, map[Steper]StepResult{y: {Status: Pending}}, w.DownstreamOf(x))
+		assert.Equal(t, Set[Steper]{x: {}, z: {}}, w.AncestorsOf(y))
+		assert.Equal(t, Set[Steper]{y: {}, after: {}}, w.DescendantsOf(x))
+		assert.Equal(t, map[Steper]StepStatus{y: Skipped, inner: Failed, after: Skipped}, w.ImpactOf(x))
+
+		layers, err := w.TopologicalLayers()
+		assert.NoError(t, err)
+		assert.Equal(t, [][]Steper{{z}, {inner}, {after}}, layers)
+	})
+	t.Run("ImpactOf matches a run", func(t *testing.T) {
+		w := newWorkflow()
+		w.Add(Step(a).Input(func(context.Context, *NoOpStep) error { return assert.AnError }))
+		impact := w.ImpactOf(a)
+		assert.Error(t, w.Do(context.Background()))
+		for step, status := range impact {
+			assert.Equal(t, status, w.StateOf(step).GetStatus(), "%s", step)
+		}
+	})
+}
@@ -0,0 +1,307 @@
+package flow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Severity grades a Diagnostic reported by Workflow.Validate.
+type Severity string
+
+const (
+	// SeverityWarning flags a configuration that runs, but most likely not
+	// the way it was meant to (e.g. a Timeout that makes retries moot).
+	SeverityWarning Severity = "warning"
+	// SeverityError flags a configuration that fails or misbehaves at Do
+	// (e.g. a dependency cycle, or a branch step that was never added).
+	SeverityError Severity = "error"
+)
+
+// Diagnostic is one problem found by Workflow.Validate.
+type Diagnostic struct {
+	Severity Severity
+	Rule     string // short identifier of the rule that reported it, e.g. "cycle".
+	Step     Steper // the Step the problem is about; nil for workflow-wide problems.
+	Message  string
+}
+
+// String renders a Diagnostic as "severity: step: message (rule)".
+func (d Diagnostic) String() string {
+	var sb strings.Builder
+	sb.WriteString(string(d.Severity))
+	sb.WriteString(": ")
+	if d.Step != nil {
+		sb.WriteString(String(d.Step))
+		sb.WriteString(": ")
+	}
+	sb.WriteString(d.Message)
+	if d.Rule != "" {
+		fmt.Fprintf(&sb, " (%s)", d.Rule)
+	}
+	return sb.String()
+}
+
+// Diagnostics is the result of Workflow.Validate.
+type Diagnostics []Diagnostic
+
+// Errors returns the Diagnostics with SeverityError.
+func (ds Diagnostics) Errors() Diagnostics {
+	var rv Diagnostics
+	for _, d := range ds {
+		if d.Severity == SeverityError {
+			rv = append(rv, d)
+		}
+	}
+	return rv
+}
+
+// Err returns an ErrValidation holding the SeverityError Diagnostics, or nil
+// if there is none. Handy in tests:
+//
+//	require.NoError(t, w.Validate().Err())
+func (ds Diagnostics) Err() error {
+	if errs := ds.Errors(); len(errs) > 0 {
+		return ErrValidation(errs)
+	}
+	return nil
+}
+
+// String renders one Diagnostic per line.
+func (ds Diagnostics) String() string {
+	lines := make([]string, 0, len(ds))
+	for _, d := range ds {
+		lines = append(lines, d.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ValidationRule is a check run by Workflow.Validate against one Workflow
+// level. Implement it to add project-specific lint rules:
+//
+//	noRetry := flow.ValidationRuleFunc(func(w *flow.Workflow) []flow.Diagnostic {
+//	    var ds []flow.Diagnostic
+//	    for _, step := range w.Steps() {
+//	        if w.StateOf(step).Option().RetryOption == nil {
+//	            ds = append(ds, flow.Diagnostic{Severity: flow.SeverityWarning, Rule: "retry", Step: step, Message: "no retry"})
+//	        }
+//	    }
+//	    return ds
+//	})
+//	diags := w.Validate(noRetry)
+//
+// Builders passed to Workflow.Add that implement ValidationRule (If and
+// Switch do) are remembered and run by Validate as well.
+type ValidationRule interface {
+	Validate(*Workflow) []Diagnostic
+}
+
+// ValidationRuleFunc adapts a function to ValidationRule.
+type ValidationRuleFunc func(*Workflow) []Diagnostic
+
+func (f ValidationRuleFunc) Validate(w *Workflow) []Diagnostic { return f(w) }
+
+// DefaultValidationRules are the rules Workflow.Validate always runs.
+var DefaultValidationRules = []ValidationRule{
+	ValidationRuleFunc(validateCycles),
+	ValidationRuleFunc(validateTimeouts),
+	ValidationRuleFunc(validateDuplicateNames),
+}
+
+// Validate statically checks the Workflow without running any Step and
+// returns what it found. It runs DefaultValidationRules, the rules of
+// ValidationRule Builders passed to Add (If / Switch), and the extra rules
+// given, against this Workflow and, recursively, every nested workflow.
+//
+// Validate sees the Step configuration declared through Add (including
+// Option.StepDefaults); contributions from Option.Mutators are applied at
+// run time and are not taken into account.
+//
+// An empty result means nothing suspicious was found; use Diagnostics.Err
+// to fail only on SeverityError.
+func (w *Workflow) Validate(rules ...ValidationRule) Diagnostics {
+	if w.Empty() {
+		return nil
+	}
+	var ds Diagnostics
+	for _, rule := range slices.Concat(DefaultValidationRules, w.rules, rules) {
+		ds = append(ds, rule.Validate(w)...)
+	}
+	for _, root := range w.order {
+		Traverse(root, func(s Steper, walked []Steper) TraverseDecision {
+			if sub, ok := s.(interface {
+				Validate(...ValidationRule) Diagnostics
+			}); ok {
+				ds = append(ds, sub.Validate(rules...)...)
+				return TraverseEndBranch
+			}
+			return TraverseContinue
+		})
+	}
+	return ds
+}
+
+// validateCycles reports every Step that can't be scheduled because it is
+// part of (or downstream of) a dependency cycle, i.e. what Do's preflight
+// would reject with ErrCycleDependency.
+func validateCycles(w *Workflow) []Diagnostic {
+	scanned := make(Set[Steper])
+	for _, layer := range w.topology().layers() {
+		scanned.Add(layer...)
+	}
+	var ds []Diagnostic
+	for _, step := range w.order {
+		if scanned.Has(step) {
+			continue
+		}
+		var blocking []string
+		for up := range w.UpstreamOf(step) {
+			if !scanned.Has(up) {
+				blocking = append(blocking, String(up))
+			}
+		}
+		slices.Sort(blocking)
+		ds = append(ds, Diagnostic{
+			Severity: SeverityError,
+			Rule:     "cycle",
+			Step:     step,
+			Message:  fmt.Sprintf("dependency cycle through [%s]", strings.Join(blocking, ", ")),
+		})
+	}
+	return ds
+}
+
+// validateTimeouts reports Steps whose step-level Timeout makes their Retry
+// configuration moot.
+func validateTimeouts(w *Workflow) []Diagnostic {
+	var ds []Diagnostic
+	for _, step := range w.order {
+		opt := w.steps[step].Option()
+		if opt.Timeout == nil {
+			continue
+		}
+		timeout := *opt.Timeout
+		switch {
+		case timeout <= 0:
+			ds = append(ds, Diagnostic{
+				Severity: SeverityWarning,
+				Rule:     "timeout",
+				Step:     step,
+				Message:  fmt.Sprintf("Timeout %s is not positive: the step's context is done before it starts", timeout),
+			})
+		case opt.RetryOption != nil && opt.RetryOption.TimeoutPerTry > 0 && timeout <= opt.RetryOption.TimeoutPerTry:
+			ds = append(ds, Diagnostic{
+				Severity: SeverityWarning,
+				Rule:     "timeout",
+				Step:     step,
+				Message: fmt.Sprintf("Timeout %s is not longer than RetryOption.TimeoutPerTry %s: the first attempt can use up the whole step deadline, leaving no time to retry",
+					timeout, opt.RetryOption.TimeoutPerTry),
+			})
+		}
+	}
+	return ds
+}
+
+// validateDuplicateNames reports root Steps rendering to the same String as
+// an earlier one: logs, errors and name-keyed tooling (Mutate by name,
+// flowrecord) can't tell them apart.
+func validateDuplicateNames(w *Workflow) []Diagnostic {
+	var ds []Diagnostic
+	seen := make(map[string]Steper)
+	for _, step := range w.order {
+		name := String(step)
+		if first, ok := seen[name]; ok {
+			ds = append(ds, Diagnostic{
+				Severity: SeverityWarning,
+				Rule:     "duplicate-name",
+				Step:     step,
+				Message:  fmt.Sprintf("has the same name as %T(%p)", first, first),
+			})
+			continue
+		}
+		seen[name] = step
+	}
+	return ds
+}
+
+// notAdded reports, as Rule rule, each of steps that isn't in w (e.g. because
+// it was attached to a branch Builder after the Builder was passed to Add).
+func notAdded(w *Workflow, rule, role string, steps ...Steper) []Diagnostic {
+	var ds []Diagnostic
+	for _, step := range steps {
+		if step == nil || w.RootOf(step) == nil {
+			ds = append(ds, Diagnostic{
+				Severity: SeverityError,
+				Rule:     rule,
+				Step:     step,
+				Message:  role + " is not in the workflow",
+			})
+		}
+	}
+	return ds
+}
+
+// Validate implements ValidationRule: it reports a Target or branch Step
+// missing from w, a missing check function, and an If without any branch.
+func (i *IfBranch[T]) Validate(w *Workflow) []Diagnostic {
+	ds := notAdded(w, "if", "If target", i.Target)
+	ds = append(ds, notAdded(w, "if", "If Then step", i.ThenStep...)...)
+	ds = append(ds, notAdded(w, "if", "If Else step", i.ElseStep...)...)
+	if i.BranchCheck.Check == nil {
+		ds = append(ds, Diagnostic{
+			Severity: SeverityError,
+			Rule:     "if",
+			Step:     i.Target,
+			Message:  "If has no check function",
+		})
+	}
+	if len(i.ThenStep) == 0 && len(i.ElseStep) == 0 {
+		ds = append(ds, Diagnostic{
+			Severity: SeverityWarning,
+			Rule:     "if",
+			Step:     i.Target,
+			Message:  "If has neither Then nor Else steps",
+		})
+	}
+	return ds
+}
+
+// Validate implements ValidationRule: it reports a Target, Case or Default
+// Step missing from w, Cases without a check function, and Default steps
+// that can never be reached or always run.
+func (s *SwitchBranch[T]) Validate(w *Workflow) []Diagnostic {
+	ds := notAdded(w, "switch", "Switch target", s.Target)
+	cases := Keys(s.CasesToCheck)
+	slices.SortFunc(cases, func(a, b Steper) int { return strings.Compare(String(a), String(b)) })
+	ds = append(ds, notAdded(w, "switch", "Switch Case step", cases...)...)
+	ds = append(ds, notAdded(w, "switch", "Switch Default step", s.DefaultStep...)...)
+	for _, c := range cases {
+		if s.CasesToCheck[c] == nil || s.CasesToCheck[c].Check == nil {
+			ds = append(ds, Diagnostic{
+				Severity: SeverityError,
+				Rule:     "switch",
+				Step:     c,
+				Message:  "Switch Case has no check function",
+			})
+		}
+	}
+	for _, d := range s.DefaultStep {
+		switch {
+		case s.CasesToCheck[d] != nil:
+			ds = append(ds, Diagnostic{
+				Severity: SeverityError,
+				Rule:     "switch",
+				Step:     d,
+				Message:  "Switch Default step is also a Case: it depends on itself and is unreachable",
+			})
+		case len(s.CasesToCheck) == 0:
+			ds = append(ds, Diagnostic{
+				Severity: SeverityWarning,
+				Rule:     "switch",
+				Step:     d,
+				Message:  "Switch has no Case: the Default step always runs",
+			})
+		}
+	}
+	return ds
+}
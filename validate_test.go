@@ -0,0 +1,118 @@
+package flow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	check := func(context.Context, *flow.NoOpStep) (bool, error) { return true, nil }
+	rules := func(ds flow.Diagnostics) []string {
+		var rv []string
+		for _, d := range ds {
+			rv = append(rv, d.Rule)
+		}
+		return rv
+	}
+
+	t.Run("clean workflow", func(t *testing.T) {
+		a, b := flow.NoOp("a"), flow.NoOp("b")
+		w := new(flow.Workflow).Add(
+			flow.Step(b).DependsOn(a).Timeout(time.Minute).Retry(func(ro *flow.RetryOption) {
+				ro.TimeoutPerTry = time.Second
+			}),
+			flow.If(a, check).Then(b),
+		)
+		assert.Empty(t, w.Validate())
+		assert.NoError(t, w.Validate().Err())
+		assert.Empty(t, new(flow.Workflow).Validate())
+	})
+	t.Run("cycle", func(t *testing.T) {
+		a, b, c := flow.NoOp("a"), flow.NoOp("b"), flow.NoOp("c")
+		w := new(flow.Workflow).Add(
+			flow.Step(a).DependsOn(b),
+			flow.Step(b).DependsOn(a),
+			flow.Step(c).DependsOn(a),
+		)
+		ds := w.Validate()
+		require.Len(t, ds, 3)
+		for _, d := range ds {
+			assert.Equal(t, flow.SeverityError, d.Severity)
+			assert.Equal(t, "cycle", d.Rule)
+		}
+		assert.Contains(t, ds.String(), "error: a: dependency cycle through [b] (cycle)")
+		var errValidation flow.ErrValidation
+		assert.ErrorAs(t, ds.Err(), &errValidation)
+		// Validate doesn't disturb the preflight check.
+		var errCycle flow.ErrCycleDependency
+		assert.ErrorAs(t, w.Do(context.Background()), &errCycle)
+	})
+	t.Run("timeout not longer than TimeoutPerTry", func(t *testing.T) {
+		a, b := flow.NoOp("a"), flow.NoOp("b")
+		w := new(flow.Workflow).Add(
+			flow.Step(a).Timeout(time.Second).Retry(func(ro *flow.RetryOption) {
+				ro.TimeoutPerTry = time.Second
+			}),
+			flow.Step(b).Timeout(0),
+		)
+		ds := w.Validate()
+		assert.Equal(t, []string{"timeout", "timeout"}, rules(ds))
+		assert.Empty(t, ds.Errors())
+		assert.NoError(t, ds.Err())
+	})
+	t.Run("duplicate names", func(t *testing.T) {
+		w := new(flow.Workflow).Add(flow.Steps(flow.NoOp("same"), flow.NoOp("same"), flow.NoOp("other")))
+		ds := w.Validate()
+		require.Len(t, ds, 1)
+		assert.Equal(t, flow.SeverityWarning, ds[0].Severity)
+		assert.Equal(t, "duplicate-name", ds[0].Rule)
+	})
+	t.Run("If", func(t *testing.T) {
+		target, then, late := flow.NoOp("target"), flow.NoOp("then"), flow.NoOp("late")
+		branch := flow.If(target, check).Then(then)
+		w := new(flow.Workflow).Add(branch)
+		assert.Empty(t, w.Validate())
+
+		branch.Else(late) // attached after Add: never part of the workflow
+		ds := w.Validate()
+		require.Len(t, ds, 1)
+		assert.Equal(t, "error: late: If Else step is not in the workflow (if)", ds[0].String())
+
+		w = new(flow.Workflow).Add(flow.If(target, nil))
+		assert.Equal(t, []string{"if", "if"}, rules(w.Validate()))
+	})
+	t.Run("Switch", func(t *testing.T) {
+		target, c, def := flow.NoOp("target"), flow.NoOp("case"), flow.NoOp("default")
+		w := new(flow.Workflow).Add(flow.Switch(target).Default(def))
+		ds := w.Validate()
+		require.Len(t, ds, 1)
+		assert.Equal(t, flow.SeverityWarning, ds[0].Severity)
+		assert.Equal(t, def, ds[0].Step)
+
+		w = new(flow.Workflow).Add(flow.Switch(target).Case(c, check).Default(c))
+		ds = w.Validate()
+		assert.Contains(t, ds.String(), "Switch Default step is also a Case")
+		assert.Error(t, ds.Err())
+	})
+	t.Run("custom rules and nested workflows", func(t *testing.T) {
+		inner := new(flow.Workflow).Add(flow.Steps(flow.NoOp("dup"), flow.NoOp("dup")))
+		w := new(flow.Workflow).Add(flow.Steps(inner, flow.NoOp("outer")))
+		noOuter := flow.ValidationRuleFunc(func(w *flow.Workflow) []flow.Diagnostic {
+			var ds []flow.Diagnostic
+			for _, step := range w.Steps() {
+				if flow.String(step) == "outer" {
+					ds = append(ds, flow.Diagnostic{Severity: flow.SeverityError, Rule: "no-outer", Step: step, Message: "forbidden"})
+				}
+			}
+			return ds
+		})
+		ds := w.Validate(noOuter)
+		assert.ElementsMatch(t, []string{"duplicate-name", "no-outer"}, rules(ds))
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/benbjohnson/clock"
@@ -95,18 +96,36 @@ type Workflow struct {
 
 	StepBuilder // embeds the BuildStep memo so Workflow.Add can call BuildStep on new steps once.
 
-	steps map[Steper]*State // root step → its State (status + StepConfig).
-
-	statusChange *sync.Cond     // signals to the tick loop when a worker terminates.
-	leaseBucket  chan struct{}  // bounded-channel "permit pool" enforcing Option.MaxConcurrency; nil means unlimited.
-	waitGroup    sync.WaitGroup // tracks worker goroutines so Do() can wait for them on exit.
-	isRunning    sync.Mutex     // single-runner guard: TryLock fails fast if Do/Reset is re-entered.
+	steps  map[Steper]*State // root step → its State (status + StepConfig).
+	order  []Steper          // root steps in the order they were first added; tick dispatches in this order.
+	index  stepIndex         // locates the steps in the roots' trees; maintained by addStep.
+	topo   *plan             // dependency graph of the root steps for the topology queries; reset by addStep.
+	edges  int               // upstreams counted when topo was built; see topology.
+	topoMu sync.Mutex
+	board  atomic.Pointer[Blackboard] // see Blackboard; created on first use.
+	preset map[Steper]StepResult      // root → the terminal result each run starts it with, instead of Pending; see From.
+	rules  []ValidationRule           // Builders passed to Add that implement ValidationRule; run by Validate.
+
+	statusChange *sync.Cond              // signals to the tick loop when a worker terminates.
+	leaseBucket  chan struct{}           // bounded-channel "permit pool" enforcing Option.MaxConcurrency; nil means unlimited.
+	waitGroup    sync.WaitGroup          // tracks worker goroutines so Do() can wait for them on exit.
+	cancel       context.CancelCauseFunc // cancels the per-Do context when Option.FailFast is set; nil otherwise.
+	drain        atomic.Pointer[drainer] // stops starting new Steps on Option.Deadline / SoftTimeout or Drain.
+	nested       bool                    // some root step contains a sub-workflow; set by Do.
+	plan         *plan                   // dependency graph of the root steps and its scheduling state; set by preflight.
+	isRunning    sync.Mutex              // single-runner guard: TryLock fails fast if Do/Reset is re-entered.
+
+	idempotency     IdempotencyStore // used when Option.IdempotencyStore is nil; created on first use.
+	idempotencyOnce sync.Once
 }
 
 // Scalar accessors: handle nil-pointer dereference and runtime defaults.
 // All in-code reads of these scalars MUST go through these accessors.
 
 func (w *Workflow) maxConcurrency() int {
+	if w.sequential() {
+		return 1
+	}
 	if w.Option.MaxConcurrency == nil {
 		return 0
 	}
@@ -121,6 +140,14 @@ func (w *Workflow) skipAsError() bool {
 	return w.Option.SkipAsError != nil && *w.Option.SkipAsError
 }
 
+func (w *Workflow) sequential() bool {
+	return w.Option.Sequential != nil && *w.Option.Sequential
+}
+
+func (w *Workflow) failFast() bool {
+	return w.Option.FailFast != nil && *w.Option.FailFast
+}
+
 func (w *Workflow) clock() clock.Clock {
 	if w.Option.Clock == nil {
 		return clock.New()
@@ -131,7 +158,9 @@ func (w *Workflow) clock() clock.Clock {
 // Add wires Builders (Step / Steps / Pipe / BatchPipe / If / Switch / …) into
 // this Workflow. Repeated calls are additive: a step that appears in multiple
 // Add() calls has its config merged (upstreams unioned, callbacks/options
-// concatenated). Returns the Workflow for chaining.
+// concatenated). The Steps of one Builder are added in the order of their
+// String, so the dispatch order doesn't depend on map iteration. Returns the
+// Workflow for chaining.
 //
 // If Option.StepDefaults is set, it is prepended to every step's Option
 // list as a SEED — so per-step Option calls (Retry, Timeout, When, …) still
@@ -157,7 +186,14 @@ func (w *Workflow) Add(was ...Builder) *Workflow {
 	}
 	for _, wa := range was {
 		if wa != nil {
-			for step, config := range wa.AddToWorkflow() {
+			if rule, ok := wa.(ValidationRule); ok {
+				w.rules = append(w.rules, rule)
+			}
+			configs := wa.AddToWorkflow()
+			steps := Keys(configs)
+			slices.SortStableFunc(steps, func(a, b Steper) int { return strings.Compare(String(a), String(b)) })
+			for _, step := range steps {
+				config := configs[step]
 				if w.Option.StepDefaults != nil && config != nil {
 					config.Option = slices.Insert(config.Option, 0, func(o *StepOption) {
 						*o = *w.Option.StepDefaults
@@ -183,7 +219,10 @@ func (w *Workflow) addStep(step Steper, config *StepConfig) {
 		return
 	}
 	w.BuildStep(step)
-	if !HasStep(w, step) {
+	w.topoMu.Lock()
+	w.topo = nil
+	w.topoMu.Unlock()
+	if w.RootOf(step) == nil {
 		// New root: scan its tree for any previously-registered roots that are
 		// now nested inside it, and absorb their config so the scheduler sees a
 		// single root per composite. Panic if the new step would clash with a
@@ -191,22 +230,30 @@ func (w *Workflow) addStep(step Steper, config *StepConfig) {
 		// double-ownership and we have no way to resolve it).
 		var oldRoots Set[Steper]
 		Traverse(step, func(s Steper, walked []Steper) TraverseDecision {
-			if r := w.RootOf(s); r != nil {
-				if r != s { // s already belongs to another root in this workflow.
+			if r, ok := w.index.roots[s]; ok && w.steps[r] != nil {
+				if r == s {
+					oldRoots.Add(r)
+					return TraverseEndBranch
+				}
+				if contains(r, s) { // s already belongs to another root in this workflow.
 					panic(fmt.Errorf("add step %p(%s) failed, another step %p(%s) already has %p(%s)",
 						step, step, r, r, s, s))
 				}
-				oldRoots.Add(r)
-				return TraverseEndBranch
 			}
 			return TraverseContinue
 		})
 		state := new(State)
 		for old := range oldRoots {
 			state.MergeConfig(w.steps[old].Config)
+			w.index.remove(old, w.steps[old])
 			delete(w.steps, old)
 		}
+		if len(oldRoots) > 0 {
+			w.order = slices.DeleteFunc(w.order, oldRoots.Has)
+		}
 		w.steps[step] = state
+		w.order = append(w.order, step)
+		w.index.add(step, state)
 	}
 	if config != nil {
 		for up := range config.Upstreams {
@@ -261,8 +308,14 @@ func (w *Workflow) setUpstream(step, up Steper) {
 		return
 	}
 	w.addStep(up, nil) // just add the upstream step
+	root := w.RootOf(step)
+	if root == step || root == up || root != w.RootOf(up) {
+		// Only this level contains both.
+		w.StateOf(root).AddUpstream(up)
+		return
+	}
 	var stepWalked, upWalked []Steper
-	Traverse(w, func(s Steper, walked []Steper) TraverseDecision {
+	Traverse(root, func(s Steper, walked []Steper) TraverseDecision {
 		if s == step {
 			stepWalked = walked
 		}
@@ -273,7 +326,7 @@ func (w *Workflow) setUpstream(step, up Steper) {
 			return TraverseStop
 		}
 		return TraverseContinue
-	})
+	}, w)
 	i := 0
 	for ; i < len(stepWalked) && i < len(upWalked); i++ {
 		if stepWalked[i] != upWalked[i] {
@@ -312,11 +365,17 @@ func (w *Workflow) Unwrap() []Steper {
 // `step`, or nil if no root contains it. A step is its own root when it was
 // added directly.
 func (w *Workflow) RootOf(step Steper) Steper {
-	if w.Empty() {
+	if w.Empty() || step == nil {
 		return nil
 	}
-	for root := range w.steps {
-		if HasStep(root, step) {
+	if root, ok := w.index.roots[step]; ok && w.steps[root] != nil {
+		// Steps in sub-workflows may have moved since indexed.
+		if _, static := w.index.states[step]; static || contains(root, step) {
+			return root
+		}
+	}
+	for root := range w.index.open {
+		if contains(root, step) {
 			return root
 		}
 	}
@@ -331,7 +390,10 @@ func (w *Workflow) StateOf(step Steper) *State {
 	if w.Empty() || step == nil {
 		return nil
 	}
-	for root := range w.steps {
+	if state, ok := w.index.states[step]; ok {
+		return state
+	}
+	for root := range w.index.open {
 		var find *State
 		Traverse(root, func(s Steper, walked []Steper) TraverseDecision {
 			if step == s {
@@ -354,6 +416,16 @@ func (w *Workflow) StateOf(step Steper) *State {
 	return nil
 }
 
+// Blackboard returns the Blackboard shared by the Steps of w's runs. Steps of
+// a sub-workflow use the Blackboard of the Workflow running it instead.
+func (w *Workflow) Blackboard() *Blackboard {
+	if board := w.board.Load(); board != nil {
+		return board
+	}
+	w.board.CompareAndSwap(nil, new(Blackboard))
+	return w.board.Load()
+}
+
 // UpstreamOf returns each direct upstream of `step` mapped to that upstream's
 // current StepResult. Upstream identities are normalised to their root step
 // (i.e. the value the scheduler tracks), so callers see exactly what the
@@ -397,25 +469,32 @@ func (w *Workflow) IsTerminated() bool {
 // parent-inherited contributions is prevented by the snapshot/restore in
 // Do(), not by Reset. Calling Reset between runs is therefore optional from
 // an Option-isolation standpoint; its purpose is purely to rewind per-step
-// status for re-execution.
+// status for re-execution, and to empty the Blackboard (but for the values
+// of preset Steps, see From).
 func (w *Workflow) Reset() error {
 	if !w.isRunning.TryLock() {
 		return ErrWorkflowIsRunning
 	}
 	defer w.isRunning.Unlock()
 	w.reset()
+	w.board.Load().retain(w.preset)
 	return nil
 }
 
-// reset is the per-Do internal reset: clear all step results back to Pending,
-// install a fresh statusChange Cond, and re-allocate the concurrency lease
-// bucket sized for Option.MaxConcurrency.
+// reset is the per-Do internal reset: clear all step results back to Pending
+// (or to their preset result, see From), install a fresh statusChange Cond,
+// and re-allocate the concurrency lease bucket sized for
+// Option.MaxConcurrency.
 //
 // reset does NOT touch w.Option: parent → child Option inheritance is
 // preserved by the snapshot/restore in Do() (see Workflow.Do).
 func (w *Workflow) reset() {
-	for _, state := range w.steps {
-		state.SetStepResult(StepResult{Status: Pending})
+	for step, state := range w.steps {
+		result, ok := w.preset[step]
+		if !ok {
+			result = StepResult{Status: Pending}
+		}
+		state.SetStepResult(result)
 	}
 	w.statusChange = sync.NewCond(&sync.Mutex{})
 	if mc := w.maxConcurrency(); mc > 0 {
@@ -432,12 +511,15 @@ func (w *Workflow) reset() {
 // Merge rules:
 //   - if w.Option.DontInherit is true, this is a no-op (restore is still
 //     non-nil but does nothing);
-//   - for each scalar pointer (MaxConcurrency, DontPanic, SkipAsError) and
-//     interface/pointer (Clock, StepDefaults) field: if the child's field is
-//     nil, the parent's value is copied in; non-nil child fields are
-//     preserved;
+//   - for each scalar pointer (MaxConcurrency, DontPanic, SkipAsError,
+//     FailFast, Sequential) and interface/pointer (Clock, StepDefaults,
+//     IdempotencyStore, Executor) field: if the child's field is nil, the
+//     parent's value is copied in; non-nil child fields are preserved;
 //   - for each slice (Mutators, StepInterceptors, AttemptInterceptors): a
-//     fresh slice equal to parent ++ child replaces the child's field.
+//     fresh slice equal to parent ++ child replaces the child's field;
+//   - for Hooks, only the parent's hooks with Inherit set are prepended;
+//   - Deadline, SoftTimeout and HardTimeout are not inherited: a
+//     sub-workflow drains with its parent through the context instead.
 //
 // The returned restore function rewinds w.Option to its pre-InheritOption
 // shape; the parent MUST defer it on every Do() exit path so the child does
@@ -457,15 +539,28 @@ func (w *Workflow) InheritOption(parent WorkflowOption) (restore func()) {
 	if w.Option.SkipAsError == nil {
 		w.Option.SkipAsError = parent.SkipAsError
 	}
+	if w.Option.FailFast == nil {
+		w.Option.FailFast = parent.FailFast
+	}
+	if w.Option.Sequential == nil {
+		w.Option.Sequential = parent.Sequential
+	}
 	if w.Option.Clock == nil {
 		w.Option.Clock = parent.Clock
 	}
 	if w.Option.StepDefaults == nil {
 		w.Option.StepDefaults = parent.StepDefaults
 	}
+	if w.Option.IdempotencyStore == nil {
+		w.Option.IdempotencyStore = parent.IdempotencyStore
+	}
+	if w.Option.Executor == nil {
+		w.Option.Executor = parent.Executor
+	}
 	w.Option.Mutators = prependSlice(parent.Mutators, w.Option.Mutators)
 	w.Option.StepInterceptors = prependSlice(parent.StepInterceptors, w.Option.StepInterceptors)
 	w.Option.AttemptInterceptors = prependSlice(parent.AttemptInterceptors, w.Option.AttemptInterceptors)
+	w.Option.Hooks = prependSlice(inheritedHooks(parent.Hooks), w.Option.Hooks)
 	return func() { w.Option = prev }
 }
 
@@ -494,13 +589,20 @@ func (w *Workflow) effectiveAttemptInterceptors() []AttemptInterceptor {
 //     Skipped also counts as success).
 //   - ErrWorkflow (a map of step → StepResult) otherwise. ErrCycleDependency
 //     is returned by preflight if the graph isn't a DAG.
+//
+// Option.Hooks run around the scheduling and may replace that value; see
+// [WorkflowHook].
 func (w *Workflow) Do(ctx context.Context) error {
 	// Single-runner guard.
 	if !w.isRunning.TryLock() {
 		return ErrWorkflowIsRunning
 	}
 	defer w.isRunning.Unlock()
+	return w.do(ctx)
+}
 
+// do is Do once the single-runner guard is held.
+func (w *Workflow) do(ctx context.Context) error {
 	// Snapshot Option so any InheritOption writes performed below (and
 	// transitively by nested workflows during their own Do() prologue) are
 	// reverted at the end of THIS Do() call. The snapshot is a shallow copy;
@@ -519,6 +621,25 @@ func (w *Workflow) Do(ctx context.Context) error {
 
 	w.reset()
 
+	// Steps share the Blackboard of the outermost Workflow run; it starts
+	// empty but for the values of the Steps keeping their result from a
+	// previous run.
+	if BlackboardFrom(ctx) == nil {
+		board := w.Blackboard()
+		board.retain(w.preset)
+		ctx = blackboardKey.With(ctx, board)
+	}
+
+	// With FailFast, the run gets its own cancelable context so a failing
+	// step can abort its siblings (see failFastOn).
+	w.cancel = nil
+	if w.failFast() {
+		var cancel context.CancelCauseFunc
+		ctx, cancel = context.WithCancelCause(ctx)
+		defer cancel(nil)
+		w.cancel = cancel
+	}
+
 	// Reject cycles before launching any work.
 	if err := w.preflight(); err != nil {
 		return err
@@ -534,8 +655,10 @@ func (w *Workflow) Do(ctx context.Context) error {
 	// its pre-InheritOption shape. Without this, repeated Do() runs of the
 	// same parent would accumulate parent contributions on the child.
 	var childRestores []func()
+	w.nested = false
 	for step := range w.steps {
 		if recv := findOptionReceiver(step); recv != nil {
+			w.nested = true
 			if r := recv.InheritOption(w.Option); r != nil {
 				childRestores = append(childRestores, r)
 			}
@@ -547,6 +670,31 @@ func (w *Workflow) Do(ctx context.Context) error {
 		}
 	}()
 
+	// Drain may be called from now on; the soft deadline is armed when
+	// scheduling starts.
+	drain := newDrainer(w)
+	w.drain.Store(drain)
+	defer func() {
+		drain.stop()
+		w.drain.CompareAndSwap(drain, nil)
+	}()
+
+	// Option.Hooks: OnStart may abort the run before anything is scheduled;
+	// OnSuccess / OnFailure / Finally may transform the outcome.
+	ctx, err := w.hooksOnStart(ctx)
+	if err == nil {
+		err = w.schedule(ctx)
+		err = w.hooksOnEnd(ctx, err)
+	}
+	return w.hooksFinally(ctx, err)
+}
+
+// schedule is the scheduling part of Do: it ticks until every step has
+// terminated, waits for the workers, and reduces the steps' results to Do's
+// outcome.
+func (w *Workflow) schedule(ctx context.Context) error {
+	ctx = w.drain.Load().arm(ctx, w.nested)
+
 	// Tick loop: each time a step terminates it Signal()s the cond, we wake
 	// up and tick() again. Inline-settled steps may unblock more steps within
 	// the same tick (no signal needed for those — see tick()).
@@ -576,89 +724,33 @@ func (w *Workflow) Do(ctx context.Context) error {
 	return err
 }
 
-// scanned is a private status used only by preflight() to mark steps it has
-// proven to be reachable in topological order. It is replaced by Pending
-// before Do() starts dispatching.
-const scanned StepStatus = "scanned"
-
 // stepExecution is the per-step worker context handed to the goroutine that
 // runs a single step. attempt is bumped after each completed attempt by the
-// retry loop.
+// retry loop, and the attempt's duration is added to running.
 type stepExecution struct {
 	w       *Workflow
 	step    Steper
 	state   *State
 	attempt uint64
+	running time.Duration
 }
 
-// isAllUpstreamScanned reports whether every upstream of a step has been
-// proved reachable by preflight (has the private "scanned" status).
-func isAllUpstreamScanned(ups map[Steper]StepResult) bool {
-	for _, up := range ups {
-		if up.Status != scanned {
-			return false
-		}
-	}
-	return true
-}
-
-// isAnyUpstreamNotTerminated reports whether at least one upstream is still
-// running / pending. The tick loop uses this to skip steps whose upstreams
-// haven't all settled yet.
-func isAnyUpstreamNotTerminated(ups map[Steper]StepResult) bool {
-	for _, up := range ups {
-		if !up.Status.IsTerminated() {
-			return true
-		}
-	}
-	return false
-}
-
-// preflight verifies the dependency graph is a DAG. It iteratively marks
-// every step whose upstreams are all already marked, until no further
-// progress is possible. Anything left unmarked sits in a cycle and is
-// reported via ErrCycleDependency.
-//
-// On success, all step statuses are reset to Pending so the tick loop can
-// dispatch them.
+// preflight verifies the dependency graph is a DAG, sorting the root steps
+// topologically (see plan.sort): anything left unsorted sits in, or
+// downstream of, a cycle and is reported via ErrCycleDependency. On success,
+// it prepares w.plan for the tick loop.
 func (w *Workflow) preflight() error {
-	// Topo-scan: mark Steps whose upstreams are all marked, repeat until fixed point.
-	for {
-		hasNewScanned := false
-		for step, state := range w.steps {
-			if state.GetStatus() == scanned {
-				continue
-			}
-			if isAllUpstreamScanned(w.UpstreamOf(step)) {
-				hasNewScanned = true
-				state.SetStatus(scanned)
-			}
-		}
-		if !hasNewScanned {
-			break
-		}
+	p := w.newPlan(w.dispatchOrder())
+	if _, err := p.sort(); err != nil {
+		return err
 	}
-
-	// Anything still unscanned participates in a cycle.
-	stepsInCycle := make(ErrCycleDependency)
-	for step, state := range w.steps {
-		if state.GetStatus() == scanned {
-			continue
-		}
-		for up, statusErr := range w.UpstreamOf(step) {
-			if statusErr.Status != scanned {
-				stepsInCycle[step] = append(stepsInCycle[step], up)
-			}
+	p.start()
+	for _, step := range p.order {
+		if w.steps[step].GetStatus().IsTerminated() { // preset
+			p.terminated(step)
 		}
 	}
-	if len(stepsInCycle) > 0 {
-		return stepsInCycle
-	}
-
-	// Reset everyone to Pending for the real run.
-	for _, step := range w.steps {
-		step.SetStepResult(StepResult{Status: Pending})
-	}
+	w.plan = p
 	return nil
 }
 
@@ -666,6 +758,12 @@ func (w *Workflow) preflight() error {
 // goroutines for every Pending step that is now eligible. Returns true iff
 // every step has reached a terminal status.
 //
+// tick doesn't rescan the steps: w.plan counts, for each step, its upstreams
+// not terminated yet, and queues the steps whose count drops to zero. Each
+// pass goes through the queue in dispatch order; a step queued during the
+// pass behind one already visited waits for the next pass, as if every step
+// were rescanned in dispatch order.
+//
 // Why Condition is evaluated HERE (under statusChange.L) rather than inside
 // the worker goroutine:
 //
@@ -681,20 +779,31 @@ func (w *Workflow) preflight() error {
 // otherwise the main Do() loop would Wait() forever for a signal that never
 // comes.
 func (w *Workflow) tick(ctx context.Context) bool {
+	p := w.plan
+	for _, step := range p.finished {
+		p.terminated(step)
+	}
+	p.finished = p.finished[:0]
 	for {
-		if w.IsTerminated() {
+		if p.remaining == 0 {
 			return true
 		}
-		progressed := false
-		for step := range w.steps {
-			state := w.StateOf(step)
-			// we only process pending Steps
-			if state.GetStatus() != Pending {
+		progressed := w.cancelPendingIfDrained()
+		var later []Steper // ready, but visited in the next pass
+		last := -1
+		for p.ready.Len() > 0 {
+			step := p.ready.pop()
+			if p.rank[step] < last {
+				later = append(later, step)
 				continue
 			}
-			// we only process Steps whose all upstreams are terminated
-			ups := w.UpstreamOf(step)
-			if isAnyUpstreamNotTerminated(ups) {
+			last = p.rank[step]
+			if w.cancelPendingIfDrained() {
+				progressed = true
+			}
+			state := w.steps[step]
+			// we only process pending Steps
+			if state.GetStatus() != Pending {
 				continue
 			}
 
@@ -717,6 +826,8 @@ func (w *Workflow) tick(ctx context.Context) bool {
 						Err:        err,
 						FinishedAt: w.clock().Now(),
 					})
+					p.terminated(step)
+					w.failFastOn(step, state, err)
 					progressed = true
 					continue
 				}
@@ -728,24 +839,43 @@ func (w *Workflow) tick(ctx context.Context) bool {
 			if option := state.Option(); option != nil && option.Condition != nil {
 				cond = option.Condition
 			}
+			ups := make(map[Steper]StepResult, len(p.ups[step]))
+			for _, up := range p.ups[step] {
+				ups[up] = w.steps[up].GetStepResult()
+			}
 			if nextStatus := cond(ctx, ups); nextStatus.IsTerminated() {
+				// Record why the step was canceled when the cancellation
+				// came from FailFast rather than from the caller's ctx.
+				var err error
+				if cause := context.Cause(ctx); nextStatus == Canceled && errors.As(cause, new(ErrFailFast)) {
+					err = cause
+				}
 				state.SetStepResult(StepResult{
 					Status:     nextStatus,
+					Err:        err,
 					FinishedAt: w.clock().Now(),
 				})
+				p.terminated(step)
 				progressed = true
 				continue
 			}
 
 			// Step will execute: take a lease and spawn a worker goroutine.
-			// SetStatus(Running) happens here (under statusChange.L) so a
+			// markRunning happens here (under statusChange.L) so a
 			// subsequent tick won't see it as Pending and double-spawn.
-			if w.lease() {
-				state.SetStatus(Running)
-				w.waitGroup.Add(1)
-				ex := &stepExecution{w: w, step: step, state: state}
-				go ex.run(ctx)
+			now := w.clock().Now()
+			state.markReady(now)
+			if !w.lease() {
+				later = append(later, step) // retried once a lease is released
+				continue
 			}
+			state.markRunning(now)
+			w.waitGroup.Add(1)
+			ex := &stepExecution{w: w, step: step, state: state}
+			go ex.run(ctx)
+		}
+		for _, step := range later {
+			p.ready.push(step)
 		}
 		// If we settled any step inline this pass, re-iterate to give downstream
 		// steps a chance to be picked up without waiting for a signal.
@@ -755,8 +885,73 @@ func (w *Workflow) tick(ctx context.Context) bool {
 	}
 }
 
-// signalStatusChange wakes the tick loop. Called from a worker goroutine
-// after the worker has updated its step's status to terminal.
+// cancelPendingIfDrained settles every Pending step Canceled once the
+// Workflow drains, as no step starts anymore. It reports whether it did.
+func (w *Workflow) cancelPendingIfDrained() bool {
+	p := w.plan
+	if p.drained {
+		return false
+	}
+	reason := w.drainedBy()
+	if reason == nil {
+		return false
+	}
+	p.drained = true
+	for _, step := range p.order {
+		state := w.steps[step]
+		if state.GetStatus() != Pending {
+			continue
+		}
+		state.SetStepResult(StepResult{
+			Status:     Canceled,
+			Err:        reason,
+			FinishedAt: w.clock().Now(),
+		})
+		p.terminated(step)
+	}
+	return true
+}
+
+// failFastOn cancels the per-Do context with an ErrFailFast cause when
+// Option.FailFast is set and the failed step hasn't opted out via
+// DontFailFast. Only the first failure is recorded as the cause; later calls
+// are no-ops.
+func (w *Workflow) failFastOn(step Steper, state *State, err error) {
+	if w.cancel != nil && !state.Option().DontFailFast {
+		w.cancel(ErrFailFast{Step: step, Err: err})
+	}
+}
+
+// dispatchOrder returns the root steps in the order tick considers them:
+// the order they were first added, or — under Option.Sequential — sorted by
+// String(step) (ties keep the added order), so that a run is reproducible
+// even though Builders are maps with no stable iteration order.
+func (w *Workflow) dispatchOrder() []Steper {
+	if !w.sequential() {
+		return w.order
+	}
+	names := make(map[Steper]string, len(w.order))
+	for _, step := range w.order {
+		names[step] = String(step)
+	}
+	order := slices.Clone(w.order)
+	slices.SortStableFunc(order, func(a, b Steper) int {
+		return strings.Compare(names[a], names[b])
+	})
+	return order
+}
+
+// finished records that the worker of root step terminated it, and wakes the
+// tick loop.
+func (w *Workflow) finished(step Steper) {
+	w.statusChange.L.Lock()
+	defer w.statusChange.L.Unlock()
+	w.plan.finished = append(w.plan.finished, step)
+	w.statusChange.Signal()
+}
+
+// signalStatusChange wakes the tick loop without a step terminating, e.g.
+// when the Workflow starts draining.
 func (w *Workflow) signalStatusChange() {
 	w.statusChange.L.Lock()
 	defer w.statusChange.L.Unlock()
@@ -764,12 +959,20 @@ func (w *Workflow) signalStatusChange() {
 }
 
 // run executes one step from start to terminal status: it builds the
-// StepInterceptor chain (innermost call is executeWithRetry, which loops over
-// attempts), runs it, classifies the result into a StepStatus, records the
-// final StepResult, releases the concurrency lease, and signals the scheduler.
+// StepInterceptor chain (innermost call is executeIdempotent, which checks the
+// idempotency key then calls executeWithRetry, which loops over attempts),
+// runs it, classifies the result into a StepStatus, records the final
+// StepResult, releases the concurrency lease, and signals the scheduler.
 func (ex *stepExecution) run(ctx context.Context) {
 	defer ex.w.waitGroup.Done()
 
+	// The Blackboard records which root Step published each value, to drop
+	// them when it runs again; Steps of sub-workflows publish on behalf of
+	// the outermost root Step.
+	if _, ok := publisherKey.From(ctx); !ok {
+		ctx = publisherKey.With(ctx, publisher{ex.step})
+	}
+
 	// Build the StepInterceptor chain. tick() has already evaluated the
 	// Condition (terminal results were settled inline) and set the status to
 	// Running, so we can dive straight in.
@@ -777,7 +980,7 @@ func (ex *stepExecution) run(ctx context.Context) {
 	// When Option.DontPanic is true, EVERY interceptor invocation is wrapped in
 	// catchPanicAsError so a panicking user interceptor cannot crash the
 	// process or leave the lease unreleased / status unsignalled.
-	stepNext := func(ctx context.Context) error { return ex.executeWithRetry(ctx) }
+	stepNext := func(ctx context.Context) error { return ex.executeIdempotent(ctx) }
 	stepICs := ex.w.effectiveStepInterceptors()
 	for i := len(stepICs) - 1; i >= 0; i-- {
 		// `ic` and `nextLocal` are declared inside the loop body with `:=`,
@@ -798,29 +1001,24 @@ func (ex *stepExecution) run(ctx context.Context) {
 
 	err := stepNext(ctx)
 
-	// Classify the error into a terminal StepStatus. Cancellation errors
-	// (context.Canceled / DeadlineExceeded / DefaultIsCanceled-recognised)
-	// are reported as Canceled rather than Failed.
-	status := StatusFromError(err)
+	// Classify the error into a terminal StepStatus, see StatusOf.
+	status := StatusOf(err)
+
+	// Keep ReadyAt / StartedAt recorded by tick.
+	result := ex.state.GetStepResult()
+	result.Status = status
+	result.Err = err
+	result.FinishedAt = ex.w.clock().Now()
+	result.Running = ex.running
+	ex.state.SetStepResult(result)
 	if status == Failed {
-		switch {
-		case DefaultIsCanceled(err),
-			errors.Is(err, context.Canceled),
-			errors.Is(err, context.DeadlineExceeded):
-			status = Canceled
-		}
+		ex.w.failFastOn(ex.step, ex.state, err)
 	}
 
-	ex.state.SetStepResult(StepResult{
-		Status:     status,
-		Err:        err,
-		FinishedAt: ex.w.clock().Now(),
-	})
-
 	// Release the lease BEFORE signalling, so when the tick loop wakes up it
 	// can immediately acquire a fresh lease for the next runnable step.
 	ex.w.unlease()
-	ex.w.signalStatusChange()
+	ex.w.finished(ex.step)
 }
 
 // executeWithRetry runs a single step's full attempt sequence under the
@@ -848,7 +1046,8 @@ func (ex *stepExecution) executeWithRetry(ctx context.Context) error {
 // per-attempt interceptors, returning a function suitable for the retry loop.
 // The chain is wrapped one final time in a function that always increments
 // ex.attempt after each completed attempt — even when an interceptor
-// short-circuits — so the attempt counter remains accurate.
+// short-circuits — so the attempt counter remains accurate, and adds the
+// attempt's duration to ex.running.
 func (ex *stepExecution) buildAttemptChain() func(context.Context) error {
 	chain := func(ctx context.Context) error {
 		return ex.runAttempt(ctx)
@@ -869,7 +1068,11 @@ func (ex *stepExecution) buildAttemptChain() func(context.Context) error {
 	}
 	inner := chain
 	return func(ctx context.Context) error {
-		defer func() { ex.attempt++ }()
+		start := ex.w.clock().Now()
+		defer func() {
+			ex.running += ex.w.clock().Since(start)
+			ex.attempt++
+		}()
 		return inner(ctx)
 	}
 }
@@ -896,7 +1099,7 @@ func (ex *stepExecution) runAttempt(ctx context.Context) error {
 	if err != nil {
 		err = ErrBeforeStep{err}
 	} else {
-		err = do(func() error { return ex.step.Do(ctxStep) })
+		err = do(func() error { return ex.w.executorFor(ex.step).Execute(ctxStep, ex.step) })
 	}
 	return do(func() error { return ex.state.After(ctxStep, ex.step, err) })
 }
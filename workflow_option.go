@@ -1,6 +1,10 @@
 package flow
 
-import "github.com/benbjohnson/clock"
+import (
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
 
 // WorkflowOption groups all configuration that a Workflow exposes to its
 // caller AND inherits from a parent Workflow when used as a sub-workflow step.
@@ -29,11 +33,59 @@ type WorkflowOption struct {
 	// failed).
 	SkipAsError *bool
 
+	// FailFast, if non-nil and true, cancels the workflow context as soon as
+	// any Step fails. Running Steps observe ctx.Done(); Pending Steps are
+	// then settled by their Condition against the canceled context, so they
+	// become Canceled under the built-in conditions while cleanup Steps
+	// gated by BeCanceled / Always still run. A Step can opt out of
+	// triggering it via AddSteps.DontFailFast.
+	//
+	// A sub-workflow inherits FailFast unless it sets its own: composite
+	// Steps whose children are meant to fail independently of each other
+	// (e.g. ParallelStep) set it to false, so a FailFast parent only sees
+	// their aggregated outcome.
+	FailFast *bool
+
+	// Sequential, if non-nil and true, runs one Step at a time and
+	// dispatches ready Steps sorted by String(step), so runs of the same
+	// graph execute in the same order. Give Steps names (Func, Name, a
+	// String method) for the order to be stable across processes. It
+	// overrides MaxConcurrency. Intended for tests; see package flowtest.
+	Sequential *bool
+
 	// Clock is the time source used for Step timeouts, per-try timeouts in
 	// the retry loop, and backoff waits. nil means real wall clock
 	// (clock.New()). Inject a clock.Mock in tests to control time.
 	Clock clock.Clock
 
+	// Deadline and SoftTimeout, if non-nil, set when the Workflow stops
+	// starting new Steps: at Deadline, or SoftTimeout after scheduling
+	// started (after the OnStart hooks), whichever comes first, on Clock.
+	// From then on, Pending Steps are settled Canceled with ErrSoftTimeout
+	// while running Steps go on; Do returns once they terminated.
+	//
+	// HardTimeout, if non-nil, is the grace period running Steps get after
+	// that soft deadline: then their context is canceled with ErrHardTimeout
+	// as cause. It also applies when the Workflow is drained otherwise
+	// (Workflow.Drain, or a draining parent).
+	//
+	// They are not inherited: a sub-workflow has its own, and also stops
+	// starting Steps when its parent does (then applying its own
+	// HardTimeout); the parent's hard timeout reaches it through the context.
+	Deadline    *time.Time
+	SoftTimeout *time.Duration
+	HardTimeout *time.Duration
+
+	// Executor runs the Steps' Do calls; nil means InProcess. See
+	// [Executor].
+	Executor Executor
+
+	// IdempotencyStore records the idempotency keys of Steps configured with
+	// AddSteps.IdempotencyKey. nil means an in-memory store private to the
+	// Workflow, kept across its runs; use a FileIdempotencyStore (or your
+	// own) for keys to survive the process.
+	IdempotencyStore IdempotencyStore
+
 	// StepDefaults, if non-nil, is prepended as the FIRST option to every
 	// Step's Option list as a baseline. Per-step Option calls (Retry,
 	// Timeout, When, …) still win over it.
@@ -52,6 +104,12 @@ type WorkflowOption struct {
 	// On inheritance, the parent's slice is prepended to the child's.
 	AttemptInterceptors []AttemptInterceptor
 
+	// Hooks run around every Do of the Workflow: before any Step is
+	// scheduled and after every Step has terminated. See [WorkflowHook].
+	// On inheritance, only the parent's hooks with Inherit set are
+	// prepended to the child's.
+	Hooks []WorkflowHook
+
 	// DontInherit, when true on a sub-workflow Workflow, makes InheritOption
 	// a no-op: nothing flows in from the parent. Replaces the previous
 	// IsolateInterceptors flag and now governs the entire WorkflowOption,
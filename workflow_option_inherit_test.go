@@ -18,6 +18,8 @@ func TestInheritOption_Scalars(t *testing.T) {
 		MaxConcurrency: ptr(4),
 		DontPanic:      ptr(true),
 		SkipAsError:    ptr(true),
+		FailFast:       ptr(true),
+		Sequential:     ptr(true),
 		Clock:          clock.NewMock(),
 		StepDefaults:   &StepOption{},
 	}
@@ -28,6 +30,8 @@ func TestInheritOption_Scalars(t *testing.T) {
 		assert.Equal(t, parent.MaxConcurrency, w.Option.MaxConcurrency)
 		assert.Equal(t, parent.DontPanic, w.Option.DontPanic)
 		assert.Equal(t, parent.SkipAsError, w.Option.SkipAsError)
+		assert.Equal(t, parent.FailFast, w.Option.FailFast)
+		assert.Equal(t, parent.Sequential, w.Option.Sequential)
 		assert.Equal(t, parent.Clock, w.Option.Clock)
 		assert.Equal(t, parent.StepDefaults, w.Option.StepDefaults)
 	})
@@ -2,7 +2,9 @@ package flow
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -151,6 +153,151 @@ func TestSkipAsError(t *testing.T) {
 	})
 }
 
+func TestFailFast(t *testing.T) {
+	t.Parallel()
+	errBoom := errors.New("boom")
+	// newFlow builds: a fails once b is running; b waits for ctx or a short
+	// timer; c depends on b; cleanup depends on b and runs Always.
+	newFlow := func() (w *Workflow, a, b, c, cleanup *Function[struct{}, struct{}], cleanupRan *atomic.Bool) {
+		bStarted := make(chan struct{})
+		cleanupRan = new(atomic.Bool)
+		a = Func("a", func(ctx context.Context) error {
+			<-bStarted
+			return errBoom
+		})
+		b = Func("b", func(ctx context.Context) error {
+			close(bStarted)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(50 * time.Millisecond):
+				return nil
+			}
+		})
+		c = Func("c", func(ctx context.Context) error { return nil })
+		cleanup = Func("cleanup", func(ctx context.Context) error {
+			cleanupRan.Store(true)
+			return nil
+		})
+		w = new(Workflow)
+		w.Add(
+			Steps(a, b),
+			Steps(c).DependsOn(b),
+			Steps(cleanup).DependsOn(b).When(Always),
+		)
+		return
+	}
+
+	t.Run("failure cancels running siblings and pending downstreams", func(t *testing.T) {
+		t.Parallel()
+		w, a, b, c, _, cleanupRan := newFlow()
+		w.Option.FailFast = ptr(true)
+		err := w.Do(context.Background())
+		var errWorkflow ErrWorkflow
+		assert.ErrorAs(t, err, &errWorkflow)
+		assert.Equal(t, Failed, errWorkflow[a].Status)
+		assert.Equal(t, Canceled, errWorkflow[b].Status)
+		assert.Equal(t, Canceled, errWorkflow[c].Status)
+		var errFailFast ErrFailFast
+		if assert.ErrorAs(t, errWorkflow[c].Err, &errFailFast) {
+			assert.Equal(t, a, errFailFast.Step)
+			assert.ErrorIs(t, errFailFast.Err, errBoom)
+		}
+		assert.True(t, cleanupRan.Load(), "Always cleanup still runs")
+	})
+
+	t.Run("siblings run to completion by default", func(t *testing.T) {
+		t.Parallel()
+		w, a, b, c, _, _ := newFlow()
+		err := w.Do(context.Background())
+		var errWorkflow ErrWorkflow
+		assert.ErrorAs(t, err, &errWorkflow)
+		assert.Equal(t, Failed, errWorkflow[a].Status)
+		assert.Equal(t, Succeeded, errWorkflow[b].Status)
+		assert.Equal(t, Succeeded, errWorkflow[c].Status)
+	})
+
+	t.Run("step can opt out", func(t *testing.T) {
+		t.Parallel()
+		w, a, b, _, _, _ := newFlow()
+		w.Option.FailFast = ptr(true)
+		w.Add(Steps(a).DontFailFast())
+		err := w.Do(context.Background())
+		var errWorkflow ErrWorkflow
+		assert.ErrorAs(t, err, &errWorkflow)
+		assert.Equal(t, Succeeded, errWorkflow[b].Status)
+	})
+
+	t.Run("BeCanceled cleanup runs after fail fast", func(t *testing.T) {
+		t.Parallel()
+		ran := false
+		fail := Func("fail", func(ctx context.Context) error { return errBoom })
+		cleanup := Func("cleanup", func(ctx context.Context) error {
+			ran = true
+			return nil
+		})
+		w := &Workflow{Option: WorkflowOption{FailFast: ptr(true)}}
+		w.Add(Steps(cleanup).DependsOn(fail).When(BeCanceled))
+		assert.Error(t, w.Do(context.Background()))
+		assert.True(t, ran)
+	})
+
+	t.Run("inherited by sub-workflow", func(t *testing.T) {
+		t.Parallel()
+		inner, a, b, _, _, _ := newFlow()
+		outer := &Workflow{Option: WorkflowOption{FailFast: ptr(true)}}
+		outer.Add(Step(inner))
+		assert.Error(t, outer.Do(context.Background()))
+		assert.Equal(t, Failed, inner.StateOf(a).GetStatus())
+		assert.Equal(t, Canceled, inner.StateOf(b).GetStatus())
+	})
+
+	t.Run("sub-workflow can opt out", func(t *testing.T) {
+		t.Parallel()
+		inner, a, b, _, _, _ := newFlow()
+		inner.Option.FailFast = ptr(false)
+		outer := &Workflow{Option: WorkflowOption{FailFast: ptr(true)}}
+		outer.Add(Step(inner))
+		assert.Error(t, outer.Do(context.Background()))
+		assert.Equal(t, Failed, inner.StateOf(a).GetStatus())
+		assert.Equal(t, Succeeded, inner.StateOf(b).GetStatus())
+	})
+}
+
+func TestSequential(t *testing.T) {
+	t.Parallel()
+	var (
+		mu      sync.Mutex
+		order   []string
+		running atomic.Int32
+		maxSeen atomic.Int32
+	)
+	record := func(name string) *Function[struct{}, struct{}] {
+		return Func(name, func(ctx context.Context) error {
+			if cur := running.Add(1); cur > maxSeen.Load() {
+				maxSeen.Store(cur)
+			}
+			defer running.Add(-1)
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return nil
+		})
+	}
+	a, b, c, d, e := record("a"), record("b"), record("c"), record("d"), record("e")
+	w := &Workflow{Option: WorkflowOption{Sequential: ptr(true), MaxConcurrency: ptr(8)}}
+	w.Add(
+		Steps(e, c, a),
+		Steps(d, b).DependsOn(c),
+	)
+	for i := 0; i < 5; i++ {
+		order = nil
+		assert.NoError(t, w.Do(context.Background()))
+		assert.Equal(t, []string{"a", "c", "b", "d", "e"}, order)
+	}
+	assert.EqualValues(t, 1, maxSeen.Load(), "Sequential overrides MaxConcurrency")
+}
+
 func TestClock(t *testing.T) {
 	t.Parallel()
 	t.Run("Nil Clock uses wall clock via accessor", func(t *testing.T) {